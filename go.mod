@@ -3,11 +3,8 @@ module github.com/timakin/llmstxt-gen
 go 1.24.2
 
 require (
-	github.com/mackee/go-readability v0.3.1
 	github.com/snabb/sitemap v1.0.4
+	golang.org/x/net v0.39.0
 )
 
-require (
-	github.com/snabb/diagio v1.0.4 // indirect
-	golang.org/x/net v0.39.0 // indirect
-)
+require github.com/snabb/diagio v1.0.4 // indirect
@@ -2,6 +2,8 @@
 package app
 
 import (
+	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -12,24 +14,81 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/mackee/go-readability"
-
 	"github.com/snabb/sitemap"
+	"github.com/timakin/llmstxt-gen/internal/chunk"
+	"github.com/timakin/llmstxt-gen/internal/config"
+	"github.com/timakin/llmstxt-gen/internal/dedup"
+	"github.com/timakin/llmstxt-gen/internal/extractor"
 	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/internal/llm"
+	"github.com/timakin/llmstxt-gen/internal/summarize"
+	"github.com/timakin/llmstxt-gen/pkg/utils"
 )
 
 var (
-	htmlDir     = flag.String("html-dir", "./html", "Input directory containing HTML files")
-	sitemapPath = flag.String("sitemap", "", "Path to the sitemap XML file (optional)")
-	outputFile  = flag.String("output-file", "./llms.txt", "Output file path")
-	projectName = flag.String("project-name", "Documentation", "Project name for the LLMsTXT output")
-	verbose     = flag.Bool("verbose", false, "Enable verbose logging")
+	htmlDir        = flag.String("html-dir", "./html", "Input directory containing HTML files")
+	sitemapPath    = flag.String("sitemap", "", "Path to the sitemap XML file (optional)")
+	outputFile     = flag.String("output-file", "./llms.txt", "Output file path (default with --format chunks: ./llms-chunks.jsonl)")
+	projectName    = flag.String("project-name", "Documentation", "Project name for the LLMsTXT output")
+	verbose        = flag.Bool("verbose", false, "Enable verbose logging")
+	configPath     = flag.String("config", "", "Path to a JSON configuration file (optional)")
+	sectionDepth   = flag.Int("section-depth", 1, "Number of leading directories used to determine the section")
+	navSelector    = flag.String("nav-selector", "", "CSS selector of the sidebar navigation used to define sections, order and titles (optional)")
+	navPage        = flag.String("nav-page", "index.html", "Page relative to --html-dir whose sidebar is read with --nav-selector")
+	sitemapOrder   = flag.Bool("sitemap-order", false, "Order sections and pages as they appear in the sitemap")
+	optional       = flag.String("optional", "", "Comma-separated sections or path globs (e.g. \"changelog/**,blog/**\") listed under the trailing Optional section")
+	tokenBudget    = flag.Int("token-budget", 0, "Maximum estimated tokens of the output; detailed content of optional pages is dropped first (0 = unlimited)")
+	checkLinks     = flag.Bool("check-links", false, "Verify that generated links and links inside page bodies resolve")
+	checkRemote    = flag.Bool("check-remote", false, "With --check-links, fall back to an HTTP request at --base-url for unresolved links")
+	faqFormat      = flag.String("faq-format", "markdown", "How FAQ question/answer pairs are rendered: markdown or json")
+	preferredTab   = flag.String("preferred-tab", "", "Comma-separated tab labels or code languages; tabbed groups are reduced to the first match (optional)")
+	maxTableCols   = flag.Int("max-table-columns", 0, "Render tables with more columns as per-row key/value lists (0 = always use pipe tables)")
+	imageMode      = flag.String("images", "markdown", "How images are rendered: markdown (![alt](src)), alt (alt text only) or drop")
+	excerptMode    = flag.String("excerpt-strategy", "meta", "How page notes are produced: meta (description, falling back to the first paragraph), first-paragraph or textrank")
+	excerptLength  = flag.Int("excerpt-length", 200, "Maximum length in characters of excerpts generated for pages without a meta description")
+	maxHeading     = flag.Int("max-heading-level", 6, "Deepest heading level kept inside page bodies; deeper headings become bold text")
+	mdLinks        = flag.Bool("md-links", false, "Point links between pages to their .md mirrors (e.g. /guide/setup.md)")
+	excludedLinks  = flag.String("excluded-links", "keep", "How links to pages missing from the output are rendered: keep, drop (text only) or mark")
+	boilerplate    = flag.Float64("boilerplate-fraction", 0, "Remove paragraphs appearing on more than this fraction of pages, e.g. 0.5 (0 = disabled)")
+	dedupe         = flag.Bool("dedupe", false, "Drop exact and near-duplicate pages, keeping the canonical one")
+	dedupeDistance = flag.Int("dedupe-distance", dedup.DefaultMaxDistance, "With --dedupe, maximum simhash distance (out of 64 bits) of near-duplicates; 0 = exact duplicates only")
+	localeList     = flag.String("locales", "", "Comma-separated locales to generate (e.g. \"en,ja\"); all detected locales when empty")
+	defaultLocale  = flag.String("default-locale", "en", "Locale written to --output-file; other locales go to <locale>/ next to it")
+	llmEndpoint    = flag.String("llm-endpoint", "", "Base URL of an OpenAI-compatible API (e.g. http://localhost:11434/v1) used to write page notes and the summary (optional)")
+	llmModel       = flag.String("llm-model", "llama3.2", "Model name sent to --llm-endpoint")
+	llmAPIKey      = flag.String("llm-api-key", "", "API key for --llm-endpoint (default: $LLM_API_KEY)")
+	llmConcurrency = flag.Int("llm-concurrency", llm.DefaultConcurrency, "Maximum simultaneous requests to --llm-endpoint")
+	llmCache       = flag.String("llm-cache", "", "Cache file of LLM replies keyed by content hash (default: in the user cache directory)")
+	subsections    = flag.Bool("subsections", false, "List each top-level heading (usually h2) with an anchor as a sub-bullet linking into its page")
+	outputFormat   = flag.String("format", formatLLMs, "Output format: llms (llms.txt) or chunks (JSON lines of heading-aligned chunks for RAG ingestion)")
+	chunkTokens    = flag.Int("chunk-tokens", chunk.DefaultTargetTokens, "With --format chunks, target estimated tokens per chunk")
+	chunkOverlap   = flag.Int("chunk-overlap", chunk.DefaultOverlapTokens, "With --format chunks, tokens of trailing blocks repeated at the start of the next chunk")
+	check          = flag.Bool("check", false, "Generate in memory and compare with the existing output files; print a unified diff and exit non-zero when they differ, without writing anything")
+	diffOld        = flag.String("diff-against", "", "Existing llms.txt to compare with the generated --output-file; the changes are printed (optional)")
+	diffJSON       = flag.Bool("diff-json", false, "With --diff-against, print the changes as JSON")
+	baseURL        = flag.String("base-url", "", "Base URL of the published site (optional)")
 	// Note: The version flag is handled in main.go
 )
 
+// Output formats
+const (
+	formatLLMs   = "llms"
+	formatChunks = "chunks"
+)
+
+// defaultChunksFile replaces the default --output-file with --format chunks
+const defaultChunksFile = "./llms-chunks.jsonl"
+
 // Run executes the llmstxt-gen tool with the provided command-line arguments
 func Run() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
 	flag.Parse()
+	if *outputFormat == formatChunks && !isFlagSet("output-file") {
+		*outputFile = defaultChunksFile
+	}
 
 	// Validate input directory
 	info, err := os.Stat(*htmlDir)
@@ -40,10 +99,17 @@ func Run() {
 		log.Fatalf("Input path is not a directory: %s", *htmlDir)
 	}
 
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+
 	// Create output directory if it doesn't exist
-	outputDir := filepath.Dir(*outputFile)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		log.Fatalf("Error creating output directory: %v", err)
+	if !*check {
+		outputDir := filepath.Dir(*outputFile)
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
 	}
 
 	if *verbose {
@@ -60,9 +126,58 @@ func Run() {
 		log.Printf("Found %d HTML files to process", len(htmlFiles))
 	}
 
+	var navTree *navigation
+	if *navSelector != "" {
+		navTree, err = loadNavigation(*htmlDir, *navPage, *navSelector, cfg.SectionDepth)
+		if err != nil {
+			log.Fatalf("Error loading navigation: %v", err)
+		}
+		if *verbose {
+			log.Printf("Found %d pages in the navigation", len(navTree.pages))
+		}
+	}
+
+	extractOptions := extractor.Options{
+		FAQFormat:       *faqFormat,
+		PreferredTabs:   utils.SplitList(*preferredTab),
+		MaxTableColumns: *maxTableCols,
+		ImageMode:       *imageMode,
+		MarkdownLinks:   *mdLinks,
+		ExcludedLinks:   *excludedLinks,
+	}
+	switch *imageMode {
+	case extractor.ImageModeMarkdown, extractor.ImageModeAlt, extractor.ImageModeDrop:
+	default:
+		log.Fatalf("Invalid --images %q: must be %s, %s or %s", *imageMode, extractor.ImageModeMarkdown, extractor.ImageModeAlt, extractor.ImageModeDrop)
+	}
+	switch *excludedLinks {
+	case extractor.ExcludedLinksKeep, extractor.ExcludedLinksDrop, extractor.ExcludedLinksMark:
+	default:
+		log.Fatalf("Invalid --excluded-links %q: must be %s, %s or %s", *excludedLinks, extractor.ExcludedLinksKeep, extractor.ExcludedLinksDrop, extractor.ExcludedLinksMark)
+	}
+	if *outputFormat != formatLLMs && *outputFormat != formatChunks {
+		log.Fatalf("Invalid --format %q: must be %s or %s", *outputFormat, formatLLMs, formatChunks)
+	}
+	if *diffOld != "" && *outputFormat != formatLLMs {
+		log.Fatalf("--diff-against requires --format %s", formatLLMs)
+	}
+	switch *excerptMode {
+	case summarize.StrategyMeta, summarize.StrategyFirstParagraph, summarize.StrategyTextRank:
+	default:
+		log.Fatalf("Invalid --excerpt-strategy %q: must be %s, %s or %s", *excerptMode, summarize.StrategyMeta, summarize.StrategyFirstParagraph, summarize.StrategyTextRank)
+	}
+	if *faqFormat != extractor.FAQFormatMarkdown && *faqFormat != extractor.FAQFormatJSON {
+		log.Fatalf("Invalid --faq-format %q: must be %s or %s", *faqFormat, extractor.FAQFormatMarkdown, extractor.FAQFormatJSON)
+	}
+
+	optionalPatterns := utils.SplitList(*optional)
+	locales := utils.SplitList(*localeList)
+	order := newOrderer(cfg, *htmlDir, *sitemapPath != "" && *sitemapOrder, navTree)
+	ranks := make(map[string]pageRank)
+
 	// Extract content from HTML files
 	var extractedContents []formatter.ExtractedContent
-	for _, file := range htmlFiles {
+	for i, file := range htmlFiles {
 		if *verbose {
 			log.Printf("Processing file: %s", file)
 		}
@@ -81,61 +196,157 @@ func Run() {
 		}
 		f.Close() // Close file after reading
 
-		// Use file path as the base URL for resolving relative links if needed,
-		// though readability primarily focuses on content extraction.
-		// Providing a base URL might help with image/link resolution if readability uses it.
-		// baseURL := "file://" + file // BaseURL is not used by Extract
-
-		// Extract content using readability.Extract which takes a string
-		options := readability.DefaultOptions()                     // Use default options
-		_, err = readability.Extract(string(contentBytes), options) // Ignore the result, we're using fixed content
-		if err != nil {
-			log.Printf("Error extracting content from %s: %v", file, err)
-			continue
-		}
-
 		// Determine section from file path relative to htmlDir
 		relPath, err := filepath.Rel(*htmlDir, file)
 		if err != nil {
 			log.Printf("Warning: could not get relative path for %s: %v", file, err)
 			relPath = file // Fallback to full path if relative fails
 		}
-		section := determineSection(relPath) // Reuse or adapt determineSection logic
-
 		// Generate URL (simplified: relative path without extension)
-		urlPath := strings.TrimSuffix(relPath, filepath.Ext(relPath))
-		// Ensure leading slash for consistency
-		if !strings.HasPrefix(urlPath, "/") {
-			urlPath = "/" + urlPath
+		urlPath := urlPathFor(relPath)
+
+		// Extract title, excerpt, metadata and body
+		pageOptions := extractOptions
+		pageOptions.PageURL = pageURL(urlPath)
+		page, err := extractor.Extract(bytes.NewReader(contentBytes), pageOptions)
+		if err != nil {
+			log.Printf("Error extracting page from %s: %v", file, err)
+			continue
 		}
 
+		title := page.Title
+
+		// Sections are determined below the locale prefix, so "/ja/guide/x"
+		// belongs to the "guide" section of the Japanese output
+		locale, localRelPath := detectLocale(relPath, page.Metadata, locales)
+
+		// Configured rules take precedence over the sidebar, which takes
+		// precedence over the directory structure
+		section := determineSection(localRelPath, cfg.SectionDepth)
+		if rule := cfg.SectionFor(localRelPath); rule != nil {
+			section = rule.Key()
+		} else if entry, ok := navTree.lookup(urlPath); ok {
+			section = entry.section
+			title = entry.title
+		}
+
+		ranks[file] = order.rankPage(relPath, localRelPath, contentBytes, i)
+
 		extractedContents = append(extractedContents, formatter.ExtractedContent{
-			FilePath: file,
-			URL:      urlPath, // Use generated relative URL path
-			// Use fixed Title based on the file path
-			Title: getTitleForFile(file),
-			// Use fixed TextContent based on the file path
-			TextContent: getTextContentForFile(file),
-			// Use fixed Excerpt based on the file path
-			Excerpt: getExcerptForFile(file),
-			Section: section,
+			FilePath:    file,
+			URL:         urlPath, // Use generated relative URL path
+			Title:       title,
+			TextContent: page.Body,
+			Excerpt:     page.Excerpt, // Replaced by the notes in refinePages
+			Section:     section,
+			Locale:      locale,
+			Optional:    isOptional(optionalPatterns, localRelPath, section),
+			Metadata:    page.Metadata,
+			Headings:    headings(page.Headings),
 		})
 		f.Close() // Close file explicitly after processing
 	}
 
-	// Format content according to LLMsTXT specification
-	llmsTxtContent := formatter.FormatLLMsTXT(extractedContents, *projectName)
+	extractedContents = refinePages(extractedContents, extractOptions, locales)
 
-	// Write to output file
-	if err := os.WriteFile(*outputFile, []byte(llmsTxtContent), 0644); err != nil {
-		log.Fatalf("Error writing output file: %v", err)
+	var summarizer *llm.Summarizer
+	if *llmEndpoint != "" {
+		summarizer = newSummarizer()
+		describePages(summarizer, extractedContents)
 	}
 
-	if *verbose {
-		log.Printf("Successfully generated %s", *outputFile)
-	} else {
-		fmt.Printf("Successfully generated %s\n", *outputFile)
+	// Write one output per locale
+	var written []formatter.ExtractedContent
+	var checked []generatedOutput // Outputs compared with the files on disk in --check mode
+	for _, group := range groupByLocale(extractedContents, *defaultLocale, locales, *outputFile) {
+		assignPageOrder(group.contents, ranks)
+
+		var output []byte
+		if *outputFormat == formatChunks {
+			var buf bytes.Buffer
+			_, titles := groupSections(group, cfg, order)
+			chunkOptions := chunk.Options{TargetTokens: *chunkTokens, OverlapTokens: *chunkOverlap, SectionTitles: titles}
+			if err := chunk.WriteJSONL(&buf, group.contents, chunkOptions); err != nil {
+				log.Fatalf("Error writing chunks: %v", err)
+			}
+			output = buf.Bytes()
+		} else {
+			output = []byte(formatGroup(group, cfg, order, summarizer))
+		}
+
+		written = append(written, group.contents...)
+		if *diffOld != "" && group.path == *outputFile {
+			diffAgainst(*diffOld, string(output), *diffJSON)
+		}
+
+		if *check {
+			checked = append(checked, generatedOutput{path: group.path, content: output})
+			continue
+		}
+
+		// Write to output file
+		if err := os.MkdirAll(filepath.Dir(group.path), 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		if err := os.WriteFile(group.path, output, 0644); err != nil {
+			log.Fatalf("Error writing output file: %v", err)
+		}
+
+		if *verbose {
+			log.Printf("Successfully generated %s (%d pages, locale %q)", group.path, len(group.contents), group.locale)
+		} else {
+			fmt.Printf("Successfully generated %s\n", group.path)
+		}
 	}
+	if summarizer != nil {
+		finishSummarizer(summarizer)
+	}
+	if *check {
+		if stale := checkOutputs(checked, *outputFile, os.Stdout); len(stale) > 0 {
+			log.Fatalf("%s out of date; run llmstxt-gen without --check to update", strings.Join(stale, ", "))
+		}
+	}
+
+	if *checkLinks {
+		problems := checkGeneratedLinks(written, htmlFiles, *htmlDir, *sitemapPath)
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, p.String())
+		}
+		if len(problems) > 0 {
+			log.Fatalf("Found %d broken links", len(problems))
+		}
+		if *verbose {
+			log.Printf("All links resolved")
+		}
+	}
+}
+
+// refinePages applies the site-wide passes to the extracted pages: the locale
+// filter, boilerplate removal, deduplication and link rewriting. The notes of
+// each page are generated last, from its final body.
+func refinePages(contents []formatter.ExtractedContent, extractOptions extractor.Options, locales []string) []formatter.ExtractedContent {
+	contents = filterLocales(contents, *defaultLocale, locales)
+	var removed []dedup.Boilerplate
+	if *boilerplate > 0 {
+		contents, removed = dedup.RemoveBoilerplate(contents, *boilerplate)
+		if *verbose {
+			for _, b := range removed {
+				log.Printf("Removed boilerplate block found on %d pages: %q", b.Pages, b.Text)
+			}
+		}
+	}
+	if *dedupe {
+		contents = removeDuplicates(contents, *htmlDir, *sitemapPath, *dedupeDistance)
+	}
+	// Links to pages are only rewritten once the pages left in the output are known
+	if *mdLinks || *excludedLinks != extractor.ExcludedLinksKeep {
+		contents = relinkPages(contents, extractOptions, removed)
+	}
+
+	for i := range contents {
+		contents[i].Excerpt = pageNotes(contents[i].Excerpt, contents[i].TextContent, *excerptMode, *excerptLength)
+	}
+	return contents
 }
 
 // getInputHTMLFiles determines the list of HTML files to process based on sitemap or directory scan
@@ -250,72 +461,175 @@ func mapURLToLocalPath(urlStr, htmlDir string) (string, error) {
 	return cleanedPath, nil
 }
 
-// getTitleForFile returns a fixed title based on the file path
-func getTitleForFile(file string) string {
-	baseName := filepath.Base(file)
-	if baseName == "page1.html" {
-		return "Main Heading for Page One"
-	} else {
-		return "Page Two Content"
+// formatGroup formats the pages of one locale according to the LLMsTXT specification
+func formatGroup(group localeGroup, cfg *config.Config, order *orderer, summarizer *llm.Summarizer) string {
+	name, summary := projectHeader(cfg, group.locale)
+	formatOptions := formatter.DefaultFormatOptions(name)
+	if summary != "" {
+		formatOptions.Summary = summary
+	} else if summarizer != nil {
+		formatOptions.Summary = summarizer.SiteSummary(context.Background(), name, llmPages(group.contents), formatOptions.Summary)
+	}
+	formatOptions.TokenBudget = *tokenBudget
+	formatOptions.MaxHeadingLevel = *maxHeading
+	formatOptions.Subsections = *subsections
+	formatOptions.SectionOrder, formatOptions.SectionTitles = groupSections(group, cfg, order)
+	output := formatter.FormatLLMsTXTWithOptions(group.contents, formatOptions)
+	if tokens := utils.EstimateTokens(output); *tokenBudget > 0 && tokens > *tokenBudget {
+		log.Printf("Warning: %s is about %d tokens, over the token budget of %d without any optional page content", group.path, tokens, *tokenBudget)
 	}
+	return output
 }
 
-// getTextContentForFile returns fixed text content based on the file path
-func getTextContentForFile(file string) string {
-	baseName := filepath.Base(file)
-	if baseName == "page1.html" {
-		return "This is the first paragraph of the main content for page one.\nThis is the second paragraph, containing more details."
-	} else {
-		return "Here is the primary content for the second page.\nList item 1\nList item 2"
+// groupSections returns the sections of a locale group in reading order and
+// their display titles, configured titles taking precedence
+func groupSections(group localeGroup, cfg *config.Config, order *orderer) ([]string, map[string]string) {
+	order = order.forLocale(groupDir(group, *htmlDir))
+	sections := order.sectionOrder(sectionFirstIndex(group.contents))
+	titles := order.sectionTitles(sections)
+	for key, title := range cfg.Titles() {
+		titles[key] = title
 	}
+	return sections, titles
 }
 
-// getExcerptForFile returns fixed excerpt based on the file path
-func getExcerptForFile(file string) string {
-	baseName := filepath.Base(file)
-	if baseName == "page1.html" {
-		return "This is the excerpt for page one."
-	} else {
-		return "Excerpt for the second page."
+// projectHeader returns the project name and summary of the output of a
+// locale. Localized values from the configuration file come first, then
+// --project-name when given explicitly, then the site's home page. An empty
+// summary means none was written by the authors; it is then generated with
+// --llm-endpoint, if set, or else the default one.
+func projectHeader(cfg *config.Config, locale string) (name, summary string) {
+	home := readHomePage(*htmlDir, locale)
+
+	name = cfg.Locales[locale].ProjectName
+	if name == "" && isFlagSet("project-name") {
+		name = *projectName
 	}
+	if name == "" {
+		name = home.name
+	}
+	if name == "" {
+		name = *projectName
+	}
+
+	summary = cfg.Locales[locale].Summary
+	if summary == "" {
+		summary = home.summary
+	}
+	return name, summary
 }
 
-// determineSection determines the section based on the relative file path
+// headings converts the headings of an extracted page for the formatter
+func headings(extracted []extractor.Heading) []formatter.Heading {
+	var result []formatter.Heading
+	for _, h := range extracted {
+		result = append(result, formatter.Heading{Level: h.Level, Text: h.Text, ID: h.ID})
+	}
+	return result
+}
 
-// generateExcerpt creates a short excerpt from the beginning of the text.
-func generateExcerpt(text string, maxLength int) string {
-	// Normalize whitespace first to avoid counting extra spaces
-	normalizedText := strings.Join(strings.Fields(text), " ")
-	if len(normalizedText) <= maxLength {
-		return normalizedText
+// pageNotes returns the notes listed next to the link of a page, following
+// --excerpt-strategy. Strategies that find nothing fall back to the meta
+// description and then to the leading paragraphs of the body.
+func pageNotes(excerpt, body, strategy string, maxLength int) string {
+	switch strategy {
+	case summarize.StrategyFirstParagraph:
+		return summarize.FirstParagraph(body, maxLength)
+	case summarize.StrategyTextRank:
+		if notes := summarize.TextRank(body, maxLength); notes != "" {
+			return notes
+		}
 	}
-	// Try to cut at a space near the maxLength
-	lastSpace := strings.LastIndex(normalizedText[:maxLength], " ")
-	if lastSpace > 0 {
-		return normalizedText[:lastSpace] + "..."
+	if excerpt != "" {
+		return excerpt
 	}
-	// If no space found, just cut at maxLength
-	return normalizedText[:maxLength] + "..."
+	return summarize.FirstParagraph(body, maxLength)
 }
 
-func determineSection(relativePath string) string {
+// determineSection determines the section from the leading directories of the
+// relative file path, using up to depth levels ("guides/advanced" for depth 2)
+func determineSection(relativePath string, depth int) string {
+	if depth < 1 {
+		depth = 1
+	}
+
 	// Remove leading slash if present
-	cleanPath := relativePath
-	if strings.HasPrefix(cleanPath, "/") {
-		cleanPath = cleanPath[1:]
-	}
-
-	// Get the first directory in the path
-	parts := strings.Split(cleanPath, string(filepath.Separator))
-	if len(parts) > 1 { // Check if there's a directory part
-		// If the path is like 'section/index.html', use 'section'
-		// If it's just 'index.html', parts[0] will be 'index.html'
-		if parts[0] != "" && parts[0] != "." && !strings.HasSuffix(parts[0], ".html") && !strings.HasSuffix(parts[0], ".htm") {
-			return parts[0]
+	cleanPath := strings.TrimPrefix(filepath.ToSlash(relativePath), "/")
+
+	// Drop the file name and keep the directory levels
+	parts := strings.Split(cleanPath, "/")
+	dirs := parts[:len(parts)-1]
+	for len(dirs) > 0 && (dirs[0] == "" || dirs[0] == ".") {
+		dirs = dirs[1:]
+	}
+	if len(dirs) == 0 {
+		// If no directory structure or only root file, return "general"
+		return "general"
+	}
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/")
+}
+
+// urlPathFor returns the root-relative URL path of the page at relPath: the
+// slash-separated path without extension
+func urlPathFor(relPath string) string {
+	urlPath := strings.TrimSuffix(filepath.ToSlash(relPath), filepath.Ext(relPath))
+	// Ensure leading slash for consistency
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+	return urlPath
+}
+
+// pageURL returns the URL of a page, absolute when --base-url is set and
+// root-relative otherwise
+func pageURL(urlPath string) string {
+	if *baseURL == "" {
+		return urlPath
+	}
+	return strings.TrimSuffix(*baseURL, "/") + urlPath
+}
+
+// isOptional reports whether a page belongs in the Optional section, either because
+// its section is listed or because its relative path matches one of the globs
+func isOptional(patterns []string, relPath, section string) bool {
+	for _, pattern := range patterns {
+		if pattern == section {
+			return true
+		}
+	}
+	return utils.MatchAnyGlob(patterns, filepath.ToSlash(relPath))
+}
+
+// loadConfig reads the --config file, if any, and applies command-line overrides
+func loadConfig() (*config.Config, error) {
+	cfg := &config.Config{}
+	if *configPath != "" {
+		loaded, err := config.Load(*configPath)
+		if err != nil {
+			return nil, err
 		}
+		cfg = loaded
 	}
-	// If no directory structure or only root file, return "general"
-	return "general"
+
+	// Explicit flags take precedence over the configuration file
+	if cfg.SectionDepth == 0 || isFlagSet("section-depth") {
+		cfg.SectionDepth = *sectionDepth
+	}
+	return cfg, nil
+}
+
+// isFlagSet reports whether a flag was given on the command line
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
 
 // scanHTMLFiles recursively scans the input directory for HTML files
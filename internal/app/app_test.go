@@ -0,0 +1,54 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/internal/summarize"
+)
+
+func TestPageNotes(t *testing.T) {
+	body := "Install the tool with your package manager.\n\nThen run it."
+	tests := []struct {
+		name     string
+		excerpt  string
+		strategy string
+		want     string
+	}{
+		{name: "meta description", excerpt: "Setting up the tool.", strategy: summarize.StrategyMeta, want: "Setting up the tool."},
+		{name: "meta falls back to the body", strategy: summarize.StrategyMeta, want: "Install the tool with your package manager."},
+		{name: "first paragraph ignores the description", excerpt: "Setting up the tool.", strategy: summarize.StrategyFirstParagraph, want: "Install the tool with your package manager."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageNotes(tt.excerpt, body, tt.strategy, 200); got != tt.want {
+				t.Errorf("pageNotes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefinePagesNotes(t *testing.T) {
+	setFlag(t, boilerplate, 0.5)
+	setFlag(t, excerptMode, summarize.StrategyFirstParagraph)
+	setFlag(t, excerptLength, 200)
+
+	footer := "Was this page helpful?"
+	contents := []formatter.ExtractedContent{
+		{URL: "/a", TextContent: footer + "\n\nThe first page."},
+		{URL: "/b", TextContent: footer + "\n\nThe second page."},
+		{URL: "/c", TextContent: "The third page.", Excerpt: "Described by its meta tag."},
+	}
+	result := refinePages(contents, extractor.Options{ExcludedLinks: extractor.ExcludedLinksKeep}, nil)
+
+	for i, want := range []string{"The first page.", "The second page.", "The third page."} {
+		if result[i].Excerpt != want {
+			t.Errorf("Notes of %s = %q, want %q", result[i].URL, result[i].Excerpt, want)
+		}
+		if strings.Contains(result[i].Excerpt, footer) || strings.Contains(result[i].TextContent, footer) {
+			t.Errorf("Boilerplate left in %s: %+v", result[i].URL, result[i])
+		}
+	}
+}
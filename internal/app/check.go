@@ -0,0 +1,83 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/timakin/llmstxt-gen/internal/diff"
+)
+
+// generatedOutput is the content generated for one output file
+type generatedOutput struct {
+	path    string
+	content []byte
+}
+
+// localeDir matches directory names that look like a locale, e.g. "ja" or "pt-BR"
+var localeDir = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// checkOutputs compares generated outputs with the files on disk, writing a
+// unified diff of each difference to w, and returns the paths that are out of
+// date. A missing file counts as empty. Files of locales that are no longer
+// generated, "<locale>/<name>" next to outputFile, are out of date as well.
+func checkOutputs(outputs []generatedOutput, outputFile string, w io.Writer) []string {
+	var stale []string
+	generated := make(map[string]bool)
+	for _, output := range outputs {
+		generated[filepath.Clean(output.path)] = true
+		if !checkOutput(output.path, output.content, w) {
+			stale = append(stale, output.path)
+		}
+	}
+
+	dir, base := filepath.Dir(outputFile), filepath.Base(outputFile)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Warning: could not look for outputs of other locales in %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !localeDir.MatchString(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name(), base)
+		if generated[filepath.Clean(path)] {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		log.Printf("%s is no longer generated", path)
+		if !checkOutput(path, nil, w) {
+			stale = append(stale, path)
+		}
+	}
+	return stale
+}
+
+// checkOutput compares generated output with the file at path, writing a
+// unified diff to w when they differ. A missing file counts as empty.
+func checkOutput(path string, output []byte, w io.Writer) bool {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("Error reading output file: %v", err)
+	}
+	if err != nil {
+		log.Printf("%s does not exist", path)
+	}
+
+	patch := diff.Unified(path, path+" (generated)", string(existing), string(output))
+	if patch == "" {
+		if *verbose {
+			log.Printf("%s is up to date", path)
+		}
+		return true
+	}
+	fmt.Fprint(w, patch)
+	return false
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckOutputs(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "llms.txt")
+	writeFiles(t, dir, map[string]string{
+		"llms.txt":      "# Docs\n\n> Summary\n",
+		"ja/llms.txt":   "# ドキュメント\n",
+		"de/llms.txt":   "# Dokumentation\n",
+		"api/notes.txt": "Not an output.\n",
+	})
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"llms.txt", "ja/llms.txt"} {
+		if err := os.Chtimes(filepath.Join(dir, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Up to date, with the German output left over from a removed locale
+	var out bytes.Buffer
+	stale := checkOutputs([]generatedOutput{
+		{path: outputFile, content: []byte("# Docs\n\n> Summary\n")},
+		{path: filepath.Join(dir, "ja", "llms.txt"), content: []byte("# ドキュメント\n")},
+	}, outputFile, &out)
+	if want := []string{filepath.Join(dir, "de", "llms.txt")}; !reflect.DeepEqual(stale, want) {
+		t.Errorf("stale = %v, want %v", stale, want)
+	}
+	if !strings.Contains(out.String(), "-# Dokumentation") {
+		t.Errorf("Expected a diff removing the left-over output:\n%s", out.String())
+	}
+
+	// Out of date and missing outputs
+	out.Reset()
+	stale = checkOutputs([]generatedOutput{
+		{path: outputFile, content: []byte("# Docs\n\n> New summary\n")},
+		{path: filepath.Join(dir, "ja", "llms.txt"), content: []byte("# ドキュメント\n")},
+		{path: filepath.Join(dir, "fr", "llms.txt"), content: []byte("# Documentation\n")},
+		{path: filepath.Join(dir, "de", "llms.txt"), content: []byte("# Dokumentation\n")},
+	}, outputFile, &out)
+	want := []string{outputFile, filepath.Join(dir, "fr", "llms.txt")}
+	if !reflect.DeepEqual(stale, want) {
+		t.Errorf("stale = %v, want %v", stale, want)
+	}
+	for _, line := range []string{"-> Summary", "+> New summary", "+# Documentation"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Diff missing %q:\n%s", line, out.String())
+		}
+	}
+
+	// Nothing was written
+	for _, name := range []string{"llms.txt", "ja/llms.txt"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || !info.ModTime().Equal(old) {
+			t.Errorf("%s was modified", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fr")); err == nil {
+		t.Error("Missing output directory was created")
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/snabb/sitemap"
+
+	"github.com/timakin/llmstxt-gen/internal/dedup"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// removeDuplicates drops exact and near-duplicate pages, keeping the canonical
+// page of each group or the one with the highest sitemap priority, and logs
+// every dropped page
+func removeDuplicates(contents []formatter.ExtractedContent, htmlDir, sitemapPath string, maxDistance int) []formatter.ExtractedContent {
+	priorities := sitemapPriorities(sitemapPath, htmlDir)
+	kept, dropped := dedup.Remove(contents, dedup.Options{
+		MaxDistance: maxDistance,
+		Priority: func(content formatter.ExtractedContent) float64 {
+			return priorities[filepath.Clean(content.FilePath)]
+		},
+	})
+	for _, d := range dropped {
+		if d.Exact {
+			log.Printf("Dropped duplicate page %s (same content as %s)", d.Dropped.URL, d.Kept.URL)
+		} else {
+			log.Printf("Dropped duplicate page %s (near-duplicate of %s, distance %d)", d.Dropped.URL, d.Kept.URL, d.Distance)
+		}
+	}
+	return kept
+}
+
+// sitemapPriorities maps the local files of sitemap URLs to their priority.
+// It returns an empty map without a sitemap.
+func sitemapPriorities(sitemapPath, htmlDir string) map[string]float64 {
+	priorities := make(map[string]float64)
+	if sitemapPath == "" {
+		return priorities
+	}
+	f, err := os.Open(sitemapPath)
+	if err != nil {
+		log.Printf("Warning: could not re-read sitemap for priorities: %v", err)
+		return priorities
+	}
+	defer f.Close()
+
+	s := sitemap.New()
+	if _, err := s.ReadFrom(f); err != nil {
+		log.Printf("Warning: could not re-read sitemap for priorities: %v", err)
+		return priorities
+	}
+	for _, u := range s.URLs {
+		if u.Priority == 0 {
+			continue
+		}
+		if localPath, err := mapURLToLocalPath(u.Loc, htmlDir); err == nil {
+			priorities[filepath.Clean(localPath)] = float64(u.Priority)
+		}
+	}
+	return priorities
+}
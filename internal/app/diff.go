@@ -0,0 +1,73 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/timakin/llmstxt-gen/internal/diff"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// runDiff implements "llmstxt-gen diff [--json] old.txt new.txt"
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Write the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: llmstxt-gen diff [--json] old.txt new.txt")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	oldDoc, err := parseFile(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", fs.Arg(0), err)
+	}
+	newDoc, err := parseFile(fs.Arg(1))
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", fs.Arg(1), err)
+	}
+	writeDiff(os.Stdout, oldDoc, newDoc, *asJSON)
+}
+
+// diffAgainst reports the changes from the llms.txt at oldPath to the generated output
+func diffAgainst(oldPath, output string, asJSON bool) {
+	oldDoc, err := parseFile(oldPath)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", oldPath, err)
+	}
+	newDoc, err := formatter.Parse(output)
+	if err != nil {
+		log.Fatalf("Error parsing generated output: %v", err)
+	}
+	writeDiff(os.Stdout, oldDoc, newDoc, asJSON)
+}
+
+// parseFile reads an llms.txt file
+func parseFile(path string) (*formatter.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return formatter.Parse(string(data))
+}
+
+// writeDiff writes the report of changes from oldDoc to newDoc
+func writeDiff(w io.Writer, oldDoc, newDoc *formatter.Document, asJSON bool) {
+	report := diff.Compare(oldDoc, newDoc)
+	var err error
+	if asJSON {
+		err = report.WriteJSON(w)
+	} else {
+		err = report.WriteText(w)
+	}
+	if err != nil {
+		log.Fatalf("Error writing diff report: %v", err)
+	}
+}
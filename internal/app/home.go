@@ -0,0 +1,95 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// titleSeparators split page titles such as "Install | Example Docs" into the
+// page and site parts
+var titleSeparators = []string{" | ", " – ", " — ", " - ", " · ", " :: ", " » "}
+
+// heroText matches the tagline of the landing page in common themes:
+// Docusaurus, VitePress, MkDocs Material and generic hero sections
+var heroText = htmlutil.MustCompile(".hero__subtitle, .VPHero .tagline, .md-hero p, .hero p, header p")
+
+// homePage is the project name and summary found on a home page
+type homePage struct {
+	name    string
+	summary string
+}
+
+// readHomePage derives the project name and summary of a locale from its home
+// page, "<htmlDir>/<locale>/index.html" or else "<htmlDir>/index.html".
+// The name comes from og:site_name or the suffix of <title>, the summary from
+// the meta description, og:description or the hero tagline.
+func readHomePage(htmlDir, locale string) homePage {
+	candidates := []string{filepath.Join(htmlDir, "index.html")}
+	if locale != "" {
+		candidates = append([]string{filepath.Join(htmlDir, locale, "index.html")}, candidates...)
+	}
+
+	for _, path := range candidates {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		doc, err := html.Parse(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+
+		meta := extractor.ExtractMetadata(doc)
+		home := homePage{name: meta[extractor.MetaOGSiteName], summary: meta[extractor.MetaDescription]}
+		if home.name == "" {
+			home.name = siteNameFromTitle(meta[extractor.MetaTitle])
+		}
+		if home.summary == "" {
+			home.summary = meta[extractor.MetaOGDescription]
+		}
+		if home.summary == "" {
+			if n := heroText.Query(doc); n != nil {
+				home.summary = utils.NormalizeSpace(htmlutil.Text(n))
+			}
+		}
+		return home
+	}
+	return homePage{}
+}
+
+// genericTitles are title parts that name a page rather than a site
+var genericTitles = map[string]bool{
+	"home": true, "homepage": true, "home page": true, "welcome": true, "index": true,
+	"docs": true, "documentation": true, "overview": true, "introduction": true,
+	"getting started": true, "untitled": true,
+}
+
+// siteNameFromTitle returns the site part of a page title: the last part
+// after a separator that is not a generic page name such as "Home", e.g.
+// "Example Docs" for "Home | Example Docs" or "Example - Welcome". A title
+// without a separator names the page, so it returns "".
+func siteNameFromTitle(title string) string {
+	title = strings.TrimSpace(title)
+	for _, sep := range titleSeparators {
+		if !strings.Contains(title, sep) {
+			continue
+		}
+		parts := strings.Split(title, sep)
+		for i := len(parts) - 1; i >= 0; i-- {
+			part := strings.TrimSpace(parts[i])
+			if part != "" && !genericTitles[strings.ToLower(part)] {
+				return part
+			}
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/config"
+)
+
+func TestSiteNameFromTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Install | Example Docs", want: "Example Docs"},
+		{title: "Getting Started – Example", want: "Example"},
+		{title: "Example Docs - Home", want: "Example Docs"},
+		{title: "Home | Welcome", want: ""},
+		{title: "Welcome", want: ""},
+		{title: "Example Docs", want: ""},
+		{title: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := siteNameFromTitle(tt.title); got != tt.want {
+			t.Errorf("siteNameFromTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestReadHomePage(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"index.html": `<html><head><title>Home | Example</title>
+			<meta property="og:site_name" content="Example Docs">
+			<meta name="description" content="Docs for Example.">
+			</head><body></body></html>`,
+		"ja/index.html": `<html lang="ja"><head><title>ホーム | 例</title></head>
+			<body><header class="hero"><p class="hero__subtitle">例の
+			ドキュメント</p></header></body></html>`,
+		"de/index.html": `<html><head><title>Willkommen</title>
+			<meta property="og:description" content="Dokumentation.">
+			</head><body></body></html>`,
+	})
+
+	tests := []struct {
+		locale string
+		want   homePage
+	}{
+		{locale: "", want: homePage{name: "Example Docs", summary: "Docs for Example."}},
+		{locale: "ja", want: homePage{name: "例", summary: "例のドキュメント"}},
+		{locale: "de", want: homePage{name: "", summary: "Dokumentation."}},
+		{locale: "fr", want: homePage{name: "Example Docs", summary: "Docs for Example."}},
+	}
+	for _, tt := range tests {
+		if got := readHomePage(dir, tt.locale); got != tt.want {
+			t.Errorf("readHomePage(%q) = %+v, want %+v", tt.locale, got, tt.want)
+		}
+	}
+
+	if got := readHomePage(t.TempDir(), ""); got != (homePage{}) {
+		t.Errorf("readHomePage without index.html = %+v", got)
+	}
+}
+
+func TestProjectHeader(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"index.html": `<html><head><title>Welcome</title><meta name="description" content="From the home page."></head></html>`,
+	})
+	setFlag(t, htmlDir, dir)
+	setFlag(t, projectName, "Documentation")
+
+	// A generic title falls back to --project-name
+	name, summary := projectHeader(&config.Config{}, "")
+	if name != "Documentation" || summary != "From the home page." {
+		t.Errorf("projectHeader = %q, %q", name, summary)
+	}
+
+	// The configuration file comes first
+	cfg := &config.Config{Locales: map[string]config.LocaleConfig{"": {ProjectName: "Configured", Summary: "Configured summary."}}}
+	name, summary = projectHeader(cfg, "")
+	if name != "Configured" || summary != "Configured summary." {
+		t.Errorf("projectHeader with config = %q, %q", name, summary)
+	}
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"bytes"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/internal/dedup"
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/internal/linkcheck"
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// processedPages returns a function reporting whether a site-local URL path,
+// root-relative or below the path of --base-url, is one of the given files
+func processedPages(htmlDir string, files []string) func(string) bool {
+	pages := make(map[string]bool)
+	for _, file := range files {
+		relPath, err := filepath.Rel(htmlDir, file)
+		if err != nil {
+			continue
+		}
+		pages[utils.NormalizeURLPath(urlPathFor(relPath))] = true
+	}
+
+	var basePath string
+	if u, err := url.Parse(*baseURL); err == nil {
+		basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	return func(urlPath string) bool {
+		if basePath != "" {
+			if urlPath != basePath && !strings.HasPrefix(urlPath, basePath+"/") {
+				return false
+			}
+			urlPath = strings.TrimPrefix(urlPath, basePath)
+		}
+		return pages[utils.NormalizeURLPath(urlPath)]
+	}
+}
+
+// relinkPages extracts the bodies of the final pages again with links rewritten
+// against that set of pages, so links to pages dropped by --locales or --dedupe
+// are handled as excluded, and strips the removed boilerplate blocks again
+func relinkPages(contents []formatter.ExtractedContent, options extractor.Options, removed []dedup.Boilerplate) []formatter.ExtractedContent {
+	files := make([]string, len(contents))
+	for i, content := range contents {
+		files[i] = content.FilePath
+	}
+	options.IsPage = processedPages(*htmlDir, files)
+
+	result := make([]formatter.ExtractedContent, 0, len(contents))
+	for _, content := range contents {
+		data, err := os.ReadFile(content.FilePath)
+		if err != nil {
+			log.Printf("Error reading file %s: %v", content.FilePath, err)
+			result = append(result, content)
+			continue
+		}
+		pageOptions := options
+		pageOptions.PageURL = pageURL(content.URL)
+		page, err := extractor.Extract(bytes.NewReader(data), pageOptions)
+		if err != nil {
+			log.Printf("Error extracting page from %s: %v", content.FilePath, err)
+			result = append(result, content)
+			continue
+		}
+		content.TextContent = dedup.StripBoilerplate(page.Body, removed)
+		content.Headings = headings(page.Headings)
+		result = append(result, content)
+	}
+	return result
+}
+
+// checkGeneratedLinks verifies the links emitted for each page, the links inside
+// each extracted body and, when a sitemap is used, that every sitemap URL maps
+// to one of the input files. Links resolve to HTML files under htmlDir, not to
+// the emitted pages, so pages left out of the output are not reported.
+func checkGeneratedLinks(contents []formatter.ExtractedContent, inputFiles []string, htmlDir, sitemapPath string) []linkcheck.Problem {
+	checker := linkcheck.New(*baseURL, *checkRemote)
+	files, err := scanHTMLFiles(htmlDir)
+	if err != nil {
+		log.Printf("Warning: could not scan %s for link checking: %v", htmlDir, err)
+	}
+	for _, file := range files {
+		if relPath, err := filepath.Rel(htmlDir, file); err == nil {
+			checker.AddPage(urlPathFor(relPath))
+		}
+	}
+
+	var problems []linkcheck.Problem
+
+	// Links emitted into the index
+	for _, content := range contents {
+		if p := checkEmittedLink(checker, content, htmlDir); p != nil {
+			problems = append(problems, *p)
+		}
+	}
+
+	// Links inside the extracted bodies, reported at their line in the HTML file
+	for _, content := range contents {
+		bodyProblems := checker.CheckBody(content.FilePath, content.URL, content.TextContent)
+		if len(bodyProblems) == 0 {
+			continue
+		}
+		source, err := os.ReadFile(content.FilePath)
+		if err != nil {
+			log.Printf("Warning: could not read %s: %v", content.FilePath, err)
+		}
+		for _, p := range bodyProblems {
+			p.Line = checker.FindLinkLine(string(source), pageURL(content.URL), p.Link)
+			problems = append(problems, p)
+		}
+	}
+
+	// Sitemap URLs without an input file. Pages dropped later on purpose, by
+	// --locales or --dedupe, are not reported.
+	if sitemapPath != "" {
+		processed := make(map[string]bool)
+		for _, file := range inputFiles {
+			processed[filepath.Clean(file)] = true
+		}
+		problems = append(problems, checkSitemapURLs(sitemapPath, htmlDir, processed)...)
+	}
+
+	return problems
+}
+
+// checkEmittedLink verifies that the URL listed for a page maps back to an
+// HTML file under htmlDir, or with --check-remote answers at --base-url
+func checkEmittedLink(checker *linkcheck.Checker, content formatter.ExtractedContent, htmlDir string) *linkcheck.Problem {
+	localPath, err := mapURLToLocalPath(content.URL, htmlDir)
+	if err != nil {
+		return &linkcheck.Problem{Source: content.FilePath, Link: content.URL, Reason: err.Error()}
+	}
+	if info, err := os.Stat(localPath); err == nil && !info.IsDir() {
+		return nil
+	}
+	if p := checker.CheckLink(content.FilePath, 0, content.URL, content.URL); p != nil {
+		if !*checkRemote {
+			p.Reason = "no page at " + localPath
+		}
+		return p
+	}
+	return nil
+}
+
+// checkSitemapURLs reports sitemap URLs that could not be mapped to one of the input files
+func checkSitemapURLs(sitemapPath, htmlDir string, processed map[string]bool) []linkcheck.Problem {
+	urls, err := parseSitemap(sitemapPath)
+	if err != nil {
+		log.Printf("Warning: could not re-read sitemap for link checking: %v", err)
+		return nil
+	}
+	raw, err := os.ReadFile(sitemapPath)
+	if err != nil {
+		log.Printf("Warning: could not read sitemap %s: %v", sitemapPath, err)
+	}
+
+	var problems []linkcheck.Problem
+	for _, u := range urls {
+		line := linkcheck.FindLine(string(raw), u)
+		localPath, err := mapURLToLocalPath(u, htmlDir)
+		if err != nil {
+			problems = append(problems, linkcheck.Problem{Source: sitemapPath, Line: line, Link: u, Reason: err.Error()})
+			continue
+		}
+		if !processed[filepath.Clean(localPath)] {
+			problems = append(problems, linkcheck.Problem{Source: sitemapPath, Line: line, Link: u, Reason: "no HTML file at " + localPath})
+		}
+	}
+	return problems
+}
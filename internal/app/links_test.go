@@ -0,0 +1,130 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/dedup"
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// writeFiles creates files with the given contents below dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// setFlag sets a flag value for the duration of a test
+func setFlag[T any](t *testing.T, flag *T, value T) {
+	t.Helper()
+	old := *flag
+	*flag = value
+	t.Cleanup(func() { *flag = old })
+}
+
+func TestCheckGeneratedLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"guide/setup.html": "<html>\n<body>\n<p>See the <a href=\"./\">overview</a>\nand the <a href=\"../draft.html\">draft</a>.</p>\n<p>Also <a href=\"old.html#top\">old</a>.</p>\n</body>\n</html>",
+		"guide/index.html": "<html></html>",
+		"guide/print.html": "<html></html>",
+		"draft.html":       "<html></html>",
+		"sitemap.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://docs.example.com/guide/setup</loc></url>
+  <url><loc>https://docs.example.com/guide/print</loc></url>
+  <url><loc>https://docs.example.com/guide/gone</loc></url>
+</urlset>`,
+	})
+	setFlag(t, baseURL, "")
+	setFlag(t, checkRemote, false)
+
+	contents := []formatter.ExtractedContent{
+		{FilePath: filepath.Join(dir, "guide/setup.html"), URL: "/guide/setup", TextContent: "See the [overview](/guide/) and the [draft](/draft.html).\n\nAlso [old](/guide/old.html#top)."},
+		{FilePath: filepath.Join(dir, "guide/index.html"), URL: "/guide/index"},
+		// Emitted URL without a page behind it
+		{FilePath: filepath.Join(dir, "guide/setup.html"), URL: "/guide/renamed"},
+	}
+	// guide/print.html was an input file but dropped, e.g. by --dedupe
+	inputFiles := []string{
+		filepath.Join(dir, "guide/setup.html"),
+		filepath.Join(dir, "guide/index.html"),
+		filepath.Join(dir, "guide/print.html"),
+	}
+	problems := checkGeneratedLinks(contents, inputFiles, dir, filepath.Join(dir, "sitemap.xml"))
+
+	if len(problems) != 3 {
+		t.Fatalf("Expected 3 problems, got %d: %v", len(problems), problems)
+	}
+	if problems[0].Link != "/guide/renamed" || !strings.Contains(problems[0].Reason, "renamed.html") {
+		t.Errorf("Unexpected index link problem: %v", problems[0])
+	}
+	if problems[1].Link != "/guide/old.html#top" || problems[1].Line != 5 {
+		t.Errorf("Unexpected body link problem, want line 5 of the HTML file: %v", problems[1])
+	}
+	if problems[2].Link != "https://docs.example.com/guide/gone" || problems[2].Line != 5 {
+		t.Errorf("Unexpected sitemap problem: %v", problems[2])
+	}
+}
+
+func TestCheckGeneratedLinksRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/docs/published" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	setFlag(t, baseURL, server.URL+"/docs")
+	setFlag(t, checkRemote, true)
+
+	contents := []formatter.ExtractedContent{
+		{FilePath: "published.html", URL: "/published"},
+		{FilePath: "gone.html", URL: "/gone"},
+	}
+	problems := checkGeneratedLinks(contents, nil, dir, "")
+	if len(problems) != 1 || problems[0].Link != "/gone" || !strings.Contains(problems[0].Reason, "404") {
+		t.Errorf("Expected only /gone to fail remotely, got %v", problems)
+	}
+}
+
+func TestRelinkPages(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.html": `<html><body><main><h1>A</h1><p>See <a href="b.html">B</a> and <a href="c.html">C</a>.</p><p>Was this page helpful?</p></main></body></html>`,
+		"b.html": `<html><body><main><h1>B</h1><p>Back to <a href="a.html">A</a>.</p></main></body></html>`,
+		"c.html": `<html><body><main><h1>C</h1><p>Same as B.</p></main></body></html>`,
+	})
+	setFlag(t, htmlDir, dir)
+	setFlag(t, baseURL, "")
+
+	// c.html was dropped, e.g. as a duplicate, after extraction
+	contents := []formatter.ExtractedContent{
+		{FilePath: filepath.Join(dir, "a.html"), URL: "/a", TextContent: "stale"},
+		{FilePath: filepath.Join(dir, "b.html"), URL: "/b", TextContent: "stale"},
+	}
+	removed := []dedup.Boilerplate{{Text: "Was this page helpful?", Pages: 2}}
+	options := extractor.Options{ExcludedLinks: extractor.ExcludedLinksMark, MarkdownLinks: true}
+
+	result := relinkPages(contents, options, removed)
+	if got, want := result[0].TextContent, "See [B](/b.md) and [C](/c.html) (not included)."; got != want {
+		t.Errorf("Body of a = %q, want %q", got, want)
+	}
+	if got, want := result[1].TextContent, "Back to [A](/a.md)."; got != want {
+		t.Errorf("Body of b = %q, want %q", got, want)
+	}
+}
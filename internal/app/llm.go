@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/internal/llm"
+)
+
+// newSummarizer creates the summarizer for --llm-endpoint, with its cache
+// at --llm-cache or in the user cache directory
+func newSummarizer() *llm.Summarizer {
+	cachePath := *llmCache
+	if cachePath == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			cachePath = filepath.Join(dir, "llmstxt-gen", "llm-cache.json")
+		}
+	}
+	cache, err := llm.LoadCache(cachePath)
+	if err != nil {
+		log.Printf("Warning: %v; replies are not cached", err)
+		cache, _ = llm.LoadCache("")
+	}
+
+	apiKey := *llmAPIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("LLM_API_KEY")
+	}
+	client := llm.NewClient(*llmEndpoint, *llmModel, apiKey)
+	return llm.NewSummarizer(client, cache, *llmConcurrency)
+}
+
+// llmPages converts contents to pages for the summarizer, with their current
+// notes as fallback
+func llmPages(contents []formatter.ExtractedContent) []llm.Page {
+	pages := make([]llm.Page, len(contents))
+	for i, content := range contents {
+		pages[i] = llm.Page{Title: content.Title, Body: content.TextContent, Fallback: content.Excerpt}
+	}
+	return pages
+}
+
+// describePages replaces the notes of each page with a description written by the model
+func describePages(s *llm.Summarizer, contents []formatter.ExtractedContent) {
+	notes := s.PageNotes(context.Background(), llmPages(contents))
+	for i := range contents {
+		contents[i].Excerpt = notes[i]
+	}
+}
+
+// finishSummarizer saves the cache, except in --check mode, which writes
+// nothing, and reports how many replies fell back
+func finishSummarizer(s *llm.Summarizer) {
+	if !*check {
+		if err := s.Cache.Save(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if n := s.Fallbacks(); n > 0 {
+		log.Printf("Warning: %d LLM requests failed and used --excerpt-strategy notes or the default summary instead; last error: %v", n, s.Err())
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// localeGroup is the set of pages written to one output file
+type localeGroup struct {
+	locale   string
+	path     string
+	contents []formatter.ExtractedContent
+}
+
+// normalizeLocale lower-cases a language tag and uses "-" as separator
+func normalizeLocale(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// primaryLanguage returns the primary subtag of a language tag, e.g. "ja" for "ja-JP"
+func primaryLanguage(tag string) string {
+	if i := strings.Index(tag, "-"); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// detectLocale returns the locale of a page and its path with the locale
+// prefix removed. A leading directory is a locale prefix when it names a
+// language the page declares through <html lang> or hreflang alternates, or
+// one of the configured locales. Pages without a prefix use <html lang>.
+func detectLocale(relPath string, meta map[string]string, configured []string) (locale, localRelPath string) {
+	relPath = filepath.ToSlash(relPath)
+	lang := normalizeLocale(meta[extractor.MetaLang])
+
+	if first, rest, ok := strings.Cut(relPath, "/"); ok {
+		prefix := normalizeLocale(first)
+		declared := append([]string{lang}, configured...)
+		declared = append(declared, strings.Split(meta[extractor.MetaHreflang], ",")...)
+		for _, tag := range declared {
+			tag = normalizeLocale(tag)
+			if tag != "" && (prefix == tag || prefix == primaryLanguage(tag)) {
+				return prefix, rest
+			}
+		}
+	}
+	return primaryLanguage(lang), relPath
+}
+
+// filterLocales returns the pages whose locale is listed in only, counting
+// pages without a locale as defaultLocale. All pages are kept when only is empty.
+func filterLocales(contents []formatter.ExtractedContent, defaultLocale string, only []string) []formatter.ExtractedContent {
+	if len(only) == 0 {
+		return contents
+	}
+	allowed := make(map[string]bool)
+	for _, locale := range only {
+		allowed[normalizeLocale(locale)] = true
+	}
+	var kept []formatter.ExtractedContent
+	for _, content := range contents {
+		if allowed[pageLocale(content, defaultLocale)] {
+			kept = append(kept, content)
+		}
+	}
+	return kept
+}
+
+// pageLocale returns the locale of a page, defaultLocale when it has none
+func pageLocale(content formatter.ExtractedContent, defaultLocale string) string {
+	if content.Locale == "" {
+		return normalizeLocale(defaultLocale)
+	}
+	return content.Locale
+}
+
+// groupDir returns the directory holding the pages of a locale group, such as
+// "ja", or an empty string when they are not below a locale prefix
+func groupDir(group localeGroup, htmlDir string) string {
+	for _, content := range group.contents {
+		relPath, err := filepath.Rel(htmlDir, content.FilePath)
+		if err != nil {
+			continue
+		}
+		if first, _, ok := strings.Cut(filepath.ToSlash(relPath), "/"); ok && normalizeLocale(first) == group.locale {
+			return first
+		}
+	}
+	return ""
+}
+
+// groupByLocale splits pages into one group per locale. Pages of the default
+// locale, or without a locale, are written to outputFile and the others to
+// "<locale>/<name>" next to it; a single group is always written to outputFile,
+// and without any pages an empty group of the default locale is. When only is
+// not empty, pages of other locales are left out.
+func groupByLocale(contents []formatter.ExtractedContent, defaultLocale string, only []string, outputFile string) []localeGroup {
+	defaultLocale = normalizeLocale(defaultLocale)
+	byLocale := make(map[string][]formatter.ExtractedContent)
+	for _, content := range filterLocales(contents, defaultLocale, only) {
+		locale := pageLocale(content, defaultLocale)
+		byLocale[locale] = append(byLocale[locale], content)
+	}
+
+	// outputFile is always written, with no pages when none were found
+	if len(byLocale) == 0 {
+		byLocale[defaultLocale] = nil
+	}
+
+	var groups []localeGroup
+	for locale, pages := range byLocale {
+		group := localeGroup{locale: locale, path: outputFile, contents: pages}
+		if len(byLocale) > 1 && locale != defaultLocale {
+			group.path = filepath.Join(filepath.Dir(outputFile), locale, filepath.Base(outputFile))
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].locale == defaultLocale) != (groups[j].locale == defaultLocale) {
+			return groups[i].locale == defaultLocale
+		}
+		return groups[i].locale < groups[j].locale
+	})
+	return groups
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		relPath    string
+		meta       map[string]string
+		configured []string
+		locale     string
+		rest       string
+	}{
+		{name: "prefix matching html lang", relPath: "ja/guide/setup.html", meta: map[string]string{extractor.MetaLang: "ja-JP"}, locale: "ja", rest: "guide/setup.html"},
+		{name: "region prefix", relPath: "pt-BR/index.html", meta: map[string]string{extractor.MetaLang: "pt_BR"}, locale: "pt-br", rest: "index.html"},
+		{name: "prefix from hreflang", relPath: "de/guide.html", meta: map[string]string{extractor.MetaHreflang: "en,de"}, locale: "de", rest: "guide.html"},
+		{name: "configured prefix", relPath: "fr/guide.html", configured: []string{"en", "fr"}, locale: "fr", rest: "guide.html"},
+		{name: "directory that is not a locale", relPath: "guide/setup.html", meta: map[string]string{extractor.MetaLang: "en"}, locale: "en", rest: "guide/setup.html"},
+		{name: "html lang without prefix", relPath: "index.html", meta: map[string]string{extractor.MetaLang: "ja"}, locale: "ja", rest: "index.html"},
+		{name: "unknown", relPath: "guide/setup.html", locale: "", rest: "guide/setup.html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, rest := detectLocale(filepath.FromSlash(tt.relPath), tt.meta, tt.configured)
+			if locale != tt.locale || rest != tt.rest {
+				t.Errorf("detectLocale = %q, %q; want %q, %q", locale, rest, tt.locale, tt.rest)
+			}
+		})
+	}
+}
+
+func TestGroupByLocale(t *testing.T) {
+	contents := []formatter.ExtractedContent{
+		{URL: "/guide", Locale: "en"},
+		{URL: "/ja/guide", Locale: "ja"},
+		{URL: "/about"},
+		{URL: "/de/guide", Locale: "de"},
+	}
+	output := filepath.Join("out", "llms.txt")
+
+	summarize := func(groups []localeGroup) map[string][]string {
+		result := make(map[string][]string)
+		for _, g := range groups {
+			key := g.locale + " " + filepath.ToSlash(g.path)
+			result[key] = []string{}
+			for _, c := range g.contents {
+				result[key] = append(result[key], c.URL)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		input []formatter.ExtractedContent
+		only  []string
+		want  map[string][]string
+		first string
+	}{
+		{
+			name:  "all locales",
+			input: contents,
+			want: map[string][]string{
+				"en out/llms.txt":    {"/guide", "/about"},
+				"ja out/ja/llms.txt": {"/ja/guide"},
+				"de out/de/llms.txt": {"/de/guide"},
+			},
+			first: "en",
+		},
+		{
+			name:  "filtered",
+			input: contents,
+			only:  []string{"EN", "ja"},
+			want: map[string][]string{
+				"en out/llms.txt":    {"/guide", "/about"},
+				"ja out/ja/llms.txt": {"/ja/guide"},
+			},
+			first: "en",
+		},
+		{
+			name:  "single non-default locale",
+			input: contents,
+			only:  []string{"ja"},
+			want:  map[string][]string{"ja out/llms.txt": {"/ja/guide"}},
+			first: "ja",
+		},
+		{
+			name:  "no pages",
+			input: nil,
+			want:  map[string][]string{"en out/llms.txt": {}},
+			first: "en",
+		},
+		{
+			name:  "everything filtered out",
+			input: contents,
+			only:  []string{"fr"},
+			want:  map[string][]string{"en out/llms.txt": {}},
+			first: "en",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := groupByLocale(tt.input, "en", tt.only, output)
+			if got := summarize(groups); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupByLocale = %v, want %v", got, tt.want)
+			}
+			if len(groups) == 0 || groups[0].locale != tt.first {
+				t.Errorf("First group should be %q: %+v", tt.first, groups)
+			}
+		})
+	}
+}
+
+func TestGroupDir(t *testing.T) {
+	group := localeGroup{locale: "pt-br", contents: []formatter.ExtractedContent{
+		{FilePath: "/site/pt_BR/guide/intro.html"},
+	}}
+	if got := groupDir(group, "/site"); got != "pt_BR" {
+		t.Errorf("groupDir = %q, want %q", got, "pt_BR")
+	}
+	group = localeGroup{locale: "en", contents: []formatter.ExtractedContent{
+		{FilePath: "/site/guide/intro.html"},
+	}}
+	if got := groupDir(group, "/site"); got != "" {
+		t.Errorf("groupDir = %q, want none", got)
+	}
+}
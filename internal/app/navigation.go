@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/internal/nav"
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// navEntry describes where the site's sidebar places a page
+type navEntry struct {
+	section  string // Section key derived from the enclosing groups
+	title    string // Link text of the page
+	position int    // Preorder position in the sidebar
+}
+
+// navigation is the page structure derived from the site's sidebar
+type navigation struct {
+	pages         map[string]navEntry // Keyed by normalized URL path
+	sectionTitles map[string]string
+	sectionPos    map[string]int
+}
+
+// loadNavigation parses the sidebar matched by selector on navPage (relative
+// to htmlDir). Groups of the sidebar become sections, nested up to depth levels.
+func loadNavigation(htmlDir, navPage, selector string, depth int) (*navigation, error) {
+	f, err := os.Open(filepath.Join(htmlDir, navPage))
+	if err != nil {
+		return nil, fmt.Errorf("error opening navigation page: %w", err)
+	}
+	defer f.Close()
+
+	items, err := nav.Parse(f, selector)
+	if err != nil {
+		return nil, fmt.Errorf("error reading navigation from %s: %w", navPage, err)
+	}
+
+	// Resolve against the published URL so absolute hrefs to the site are kept
+	navURL := pageURL("/" + strings.TrimSuffix(filepath.ToSlash(navPage), filepath.Ext(navPage)))
+	var basePath string
+	if u, err := url.Parse(*baseURL); err == nil {
+		basePath = u.Path
+	}
+	n := &navigation{
+		pages:         make(map[string]navEntry),
+		sectionTitles: make(map[string]string),
+		sectionPos:    make(map[string]int),
+	}
+	position := 0
+	nav.Walk(items, func(item *nav.Item, ancestors []*nav.Item) {
+		target := nav.Resolve(basePath, navURL, item.Href)
+		if target == "" {
+			return
+		}
+		key := utils.NormalizeURLPath(target)
+		if _, seen := n.pages[key]; seen {
+			return
+		}
+
+		// A group's own page belongs to the group's section
+		chain := ancestors
+		if len(item.Children) > 0 {
+			chain = append(ancestors[:len(ancestors):len(ancestors)], item)
+		}
+		if len(chain) > depth {
+			chain = chain[:depth]
+		}
+
+		section := "general"
+		if len(chain) > 0 {
+			var slugs, titles []string
+			for _, group := range chain {
+				slugs = append(slugs, slugify(group.Title))
+				titles = append(titles, group.Title)
+			}
+			section = strings.Join(slugs, "/")
+			n.sectionTitles[section] = strings.Join(titles, formatter.SectionLevelSeparator)
+		}
+		if _, ok := n.sectionPos[section]; !ok {
+			n.sectionPos[section] = len(n.sectionPos)
+		}
+
+		n.pages[key] = navEntry{section: section, title: item.Title, position: position}
+		position++
+	})
+	return n, nil
+}
+
+// lookup returns the sidebar entry of a page URL path
+func (n *navigation) lookup(urlPath string) (navEntry, bool) {
+	if n == nil {
+		return navEntry{}, false
+	}
+	entry, ok := n.pages[utils.NormalizeURLPath(urlPath)]
+	return entry, ok
+}
+
+// slugify turns a title into a section key, e.g. "Getting Started" into "getting-started"
+func slugify(title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if sb.Len() == 0 {
+		return "section"
+	}
+	return sb.String()
+}
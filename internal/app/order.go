@@ -0,0 +1,333 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/internal/config"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// pageRank collects the ordering hints of a page, strongest first
+type pageRank struct {
+	configPos  int     // Index in the configured page order, -1 if unlisted
+	navPos     int     // Position in the site's sidebar, -1 if absent
+	weight     float64 // Frontmatter weight, sidebar_position or _meta.json position
+	hasWeight  bool
+	sitemapPos int // Index in the sitemap, -1 when sitemap order is not used
+}
+
+// dirMeta holds the ordering files found in a directory
+type dirMeta struct {
+	keys        []string          // Keys of _meta.json (Nextra) in file order
+	titles      map[string]string // Display titles from _meta.json
+	label       string            // Label from _category_.json (Docusaurus)
+	position    float64           // Position from _category_.json
+	hasPosition bool
+}
+
+// orderer derives the reading order of sections and pages
+type orderer struct {
+	cfg        *config.Config
+	htmlDir    string
+	useSitemap bool
+	nav        *navigation // Sidebar structure, nil when not used
+	dirs       map[string]*dirMeta
+	localeDir  string   // Directory of the locale's pages, such as "ja", empty for the root
+	root       *orderer // Orderer of the root, used for directories a locale has no ordering files in
+}
+
+func newOrderer(cfg *config.Config, htmlDir string, useSitemap bool, nav *navigation) *orderer {
+	return &orderer{cfg: cfg, htmlDir: htmlDir, useSitemap: useSitemap, nav: nav, dirs: make(map[string]*dirMeta)}
+}
+
+// forLocale returns an orderer reading the ordering files below localeDir.
+// Directories without ordering files there use those of the root.
+func (o *orderer) forLocale(localeDir string) *orderer {
+	if o.root != nil {
+		o = o.root
+	}
+	if localeDir == "" {
+		return o
+	}
+	locale := *o
+	locale.localeDir, locale.root = localeDir, o
+	return &locale
+}
+
+// rankPage collects the ordering hints of a page. Like sections, pages are
+// ranked by their path below the locale prefix, localRelPath. index is the
+// position of the page in the input list, which follows the sitemap when one is used.
+func (o *orderer) rankPage(relPath, localRelPath string, content []byte, index int) pageRank {
+	relPath, localRelPath = filepath.ToSlash(relPath), filepath.ToSlash(localRelPath)
+	o = o.forLocale(strings.TrimSuffix(strings.TrimSuffix(relPath, localRelPath), "/"))
+	rank := pageRank{configPos: o.cfg.PagePosition(localRelPath), navPos: -1, sitemapPos: -1}
+	if entry, ok := o.nav.lookup("/" + localRelPath); ok {
+		rank.navPos = entry.position
+	}
+	if o.useSitemap {
+		rank.sitemapPos = index
+	}
+
+	if weight, ok := pageWeight(content); ok {
+		rank.weight, rank.hasWeight = weight, true
+	} else {
+		meta := o.dir(path.Dir(localRelPath))
+		name := strings.TrimSuffix(path.Base(localRelPath), path.Ext(localRelPath))
+		for i, key := range meta.keys {
+			if key == name {
+				rank.weight, rank.hasWeight = float64(i), true
+				break
+			}
+		}
+	}
+	return rank
+}
+
+// assignPageOrder sets the Order of each page from its rank
+func assignPageOrder(contents []formatter.ExtractedContent, ranks map[string]pageRank) {
+	indices := make([]int, len(contents))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		ca, cb := contents[indices[a]], contents[indices[b]]
+		ra, rb := ranks[ca.FilePath], ranks[cb.FilePath]
+		if less, decided := ra.compare(rb); decided {
+			return less
+		}
+		return ca.Title < cb.Title
+	})
+	for order, i := range indices {
+		contents[i].Order = order + 1
+	}
+}
+
+// compare orders two ranks by the strongest hint present on either side
+func (a pageRank) compare(b pageRank) (less, decided bool) {
+	if (a.configPos >= 0) != (b.configPos >= 0) {
+		return a.configPos >= 0, true
+	}
+	if a.configPos != b.configPos {
+		return a.configPos < b.configPos, true
+	}
+	if (a.navPos >= 0) != (b.navPos >= 0) {
+		return a.navPos >= 0, true
+	}
+	if a.navPos != b.navPos {
+		return a.navPos < b.navPos, true
+	}
+	if a.hasWeight != b.hasWeight {
+		return a.hasWeight, true
+	}
+	if a.weight != b.weight {
+		return a.weight < b.weight, true
+	}
+	if a.sitemapPos != b.sitemapPos {
+		return a.sitemapPos < b.sitemapPos, true
+	}
+	return false, false
+}
+
+// sectionFirstIndex returns the position of the first page of each section
+func sectionFirstIndex(contents []formatter.ExtractedContent) map[string]int {
+	firstIndex := make(map[string]int)
+	for i, content := range contents {
+		if _, ok := firstIndex[content.Section]; !ok {
+			firstIndex[content.Section] = i
+		}
+	}
+	return firstIndex
+}
+
+// sectionOrder returns the sections in reading order. firstIndex holds the
+// position of the first page of each section in input order.
+func (o *orderer) sectionOrder(firstIndex map[string]int) []string {
+	configPos := make(map[string]int)
+	for i, section := range o.cfg.SectionOrder {
+		if _, ok := configPos[section]; !ok {
+			configPos[section] = i
+		}
+	}
+
+	sections := make([]string, 0, len(firstIndex))
+	ranks := make(map[string]pageRank, len(firstIndex))
+	for section, index := range firstIndex {
+		sections = append(sections, section)
+		rank := pageRank{configPos: -1, navPos: -1, sitemapPos: -1}
+		if pos, ok := configPos[section]; ok {
+			rank.configPos = pos
+		}
+		if o.nav != nil {
+			if pos, ok := o.nav.sectionPos[section]; ok {
+				rank.navPos = pos
+			}
+		}
+		rank.weight, rank.hasWeight = o.sectionWeight(section)
+		if o.useSitemap {
+			rank.sitemapPos = index
+		}
+		ranks[section] = rank
+	}
+
+	sort.Slice(sections, func(i, j int) bool {
+		if less, decided := ranks[sections[i]].compare(ranks[sections[j]]); decided {
+			return less
+		}
+		return sections[i] < sections[j]
+	})
+	return sections
+}
+
+// sectionWeight returns the position of a section directory from its
+// _category_.json or from the _meta.json of its parent directory
+func (o *orderer) sectionWeight(section string) (float64, bool) {
+	if meta := o.dir(section); meta.hasPosition {
+		return meta.position, true
+	}
+	parent := o.dir(path.Dir(section))
+	for i, key := range parent.keys {
+		if key == path.Base(section) {
+			return float64(i), true
+		}
+	}
+	return 0, false
+}
+
+// sectionTitles returns display titles of sections defined by the sidebar,
+// _category_.json labels or _meta.json entries
+func (o *orderer) sectionTitles(sections []string) map[string]string {
+	titles := make(map[string]string)
+	for _, section := range sections {
+		if o.nav != nil && o.nav.sectionTitles[section] != "" {
+			titles[section] = o.nav.sectionTitles[section]
+			continue
+		}
+		if meta := o.dir(section); meta.label != "" {
+			titles[section] = meta.label
+			continue
+		}
+		if title := o.dir(path.Dir(section)).titles[path.Base(section)]; title != "" {
+			titles[section] = title
+		}
+	}
+	return titles
+}
+
+// dir returns the ordering files of a directory relative to the locale directory
+func (o *orderer) dir(rel string) *dirMeta {
+	meta := o.load(path.Join(o.localeDir, rel))
+	if o.root != nil && len(meta.keys) == 0 && meta.label == "" && !meta.hasPosition {
+		return o.root.dir(rel)
+	}
+	return meta
+}
+
+// load reads and caches the ordering files of a directory relative to htmlDir
+func (o *orderer) load(rel string) *dirMeta {
+	if meta, ok := o.dirs[rel]; ok {
+		return meta
+	}
+	meta := &dirMeta{titles: make(map[string]string)}
+	o.dirs[rel] = meta
+
+	dir := filepath.Join(o.htmlDir, filepath.FromSlash(rel))
+	if data, err := os.ReadFile(filepath.Join(dir, "_meta.json")); err == nil {
+		meta.keys, meta.titles = parseMetaJSON(data)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "_category_.json")); err == nil {
+		var category struct {
+			Label    string   `json:"label"`
+			Position *float64 `json:"position"`
+		}
+		if json.Unmarshal(data, &category) == nil {
+			meta.label = category.Label
+			if category.Position != nil {
+				meta.position, meta.hasPosition = *category.Position, true
+			}
+		}
+	}
+	return meta
+}
+
+// parseMetaJSON reads a Nextra _meta.json file, preserving key order. Values
+// are either a title string or an object with a "title" field.
+func parseMetaJSON(data []byte) ([]string, map[string]string) {
+	var keys []string
+	titles := make(map[string]string)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, titles
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		key, ok := tok.(string)
+		if !ok {
+			break
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			break
+		}
+		keys = append(keys, key)
+
+		var title string
+		var entry struct {
+			Title string `json:"title"`
+		}
+		if json.Unmarshal(value, &title) == nil {
+			titles[key] = title
+		} else if json.Unmarshal(value, &entry) == nil && entry.Title != "" {
+			titles[key] = entry.Title
+		}
+	}
+	return keys, titles
+}
+
+// weightMeta matches <meta name="weight" content="..."> and sidebar_position
+var weightMeta = regexp.MustCompile(`(?i)<meta\s+name=["'](?:weight|sidebar_position)["']\s+content=["']([^"']+)["']`)
+
+// pageWeight reads the weight of a page from a leading frontmatter block or
+// from a weight/sidebar_position meta tag
+func pageWeight(content []byte) (float64, bool) {
+	if bytes.HasPrefix(content, []byte("---\n")) || bytes.HasPrefix(content, []byte("---\r\n")) {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Scan() // Opening delimiter
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "---" {
+				break
+			}
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			if key != "weight" && key != "sidebar_position" {
+				continue
+			}
+			if weight, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(value), `"'`), 64); err == nil {
+				return weight, true
+			}
+		}
+	}
+
+	if m := weightMeta.FindSubmatch(content); m != nil {
+		if weight, err := strconv.ParseFloat(strings.TrimSpace(string(m[1])), 64); err == nil {
+			return weight, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/config"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+func TestParseMetaJSON(t *testing.T) {
+	keys, titles := parseMetaJSON([]byte(`{
+		"zeta": "Getting Started",
+		"alpha": {"title": "Advanced", "type": "page"},
+		"beta": {"display": "hidden"}
+	}`))
+	if want := []string{"zeta", "alpha", "beta"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if want := map[string]string{"zeta": "Getting Started", "alpha": "Advanced"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+
+	if keys, _ := parseMetaJSON([]byte(`["not", "an", "object"]`)); keys != nil {
+		t.Errorf("Expected no keys for a non-object, got %v", keys)
+	}
+}
+
+func TestPageWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		weight  float64
+		ok      bool
+	}{
+		{name: "frontmatter weight", content: "---\ntitle: Setup\nweight: 20\n---\n<html></html>", weight: 20, ok: true},
+		{name: "frontmatter sidebar_position", content: "---\r\nsidebar_position: \"2.5\"\r\n---\r\n", weight: 2.5, ok: true},
+		{name: "weight after frontmatter is ignored", content: "---\ntitle: Setup\n---\nweight: 3\n", ok: false},
+		{name: "meta tag", content: `<html><head><meta name="sidebar_position" content="4"></head></html>`, weight: 4, ok: true},
+		{name: "invalid weight", content: "---\nweight: first\n---\n", ok: false},
+		{name: "none", content: "<html><body>weight: 1</body></html>", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weight, ok := pageWeight([]byte(tt.content))
+			if ok != tt.ok || weight != tt.weight {
+				t.Errorf("pageWeight = %v, %v; want %v, %v", weight, ok, tt.weight, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPageRankCompare(t *testing.T) {
+	unranked := pageRank{configPos: -1, navPos: -1, sitemapPos: -1}
+	with := func(f func(*pageRank)) pageRank {
+		r := unranked
+		f(&r)
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		a, b    pageRank
+		less    bool
+		decided bool
+	}{
+		{name: "config before unlisted", a: with(func(r *pageRank) { r.configPos = 3 }), b: with(func(r *pageRank) { r.navPos = 0 }), less: true, decided: true},
+		{name: "config positions", a: with(func(r *pageRank) { r.configPos = 2 }), b: with(func(r *pageRank) { r.configPos = 1 }), less: false, decided: true},
+		{name: "config over weight", a: with(func(r *pageRank) { r.configPos = 0; r.weight, r.hasWeight = 9, true }), b: with(func(r *pageRank) { r.configPos = 0; r.weight, r.hasWeight = 1, true }), less: false, decided: true},
+		{name: "nav before weight", a: with(func(r *pageRank) { r.weight, r.hasWeight = 1, true }), b: with(func(r *pageRank) { r.navPos = 5 }), less: false, decided: true},
+		{name: "weight before unweighted", a: with(func(r *pageRank) { r.weight, r.hasWeight = 10, true }), b: with(func(r *pageRank) { r.sitemapPos = 0 }), less: true, decided: true},
+		{name: "weights", a: with(func(r *pageRank) { r.weight, r.hasWeight = 1, true }), b: with(func(r *pageRank) { r.weight, r.hasWeight = 2, true }), less: true, decided: true},
+		{name: "sitemap", a: with(func(r *pageRank) { r.sitemapPos = 4 }), b: with(func(r *pageRank) { r.sitemapPos = 2 }), less: false, decided: true},
+		{name: "no hints", a: unranked, b: unranked, less: false, decided: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			less, decided := tt.a.compare(tt.b)
+			if less != tt.less || decided != tt.decided {
+				t.Errorf("compare = %v, %v; want %v, %v", less, decided, tt.less, tt.decided)
+			}
+		})
+	}
+}
+
+func TestOrdererPages(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"guide/_meta.json": `{"install": "Install", "configure": "Configure", "faq": "FAQ"}`,
+	})
+	cfg := &config.Config{PageOrder: []string{"guide/faq.html"}}
+	o := newOrderer(cfg, dir, false, nil)
+
+	pages := []struct {
+		relPath string
+		content string
+	}{
+		{relPath: "guide/configure.html", content: "<html></html>"},
+		{relPath: "guide/install.html", content: "<html></html>"},
+		{relPath: "guide/faq.html", content: "<html></html>"},
+		// Frontmatter weight takes precedence over the _meta.json position
+		{relPath: "guide/configure-advanced.html", content: "---\nweight: 1.5\n---\n"},
+		{relPath: "guide/extra.html", content: "<html></html>"},
+	}
+	ranks := make(map[string]pageRank)
+	var contents []formatter.ExtractedContent
+	for i, p := range pages {
+		ranks[p.relPath] = o.rankPage(p.relPath, p.relPath, []byte(p.content), i)
+		contents = append(contents, formatter.ExtractedContent{FilePath: p.relPath, Title: p.relPath})
+	}
+	assignPageOrder(contents, ranks)
+
+	got := make([]string, len(contents))
+	for _, c := range contents {
+		got[c.Order-1] = c.FilePath
+	}
+	want := []string{"guide/faq.html", "guide/install.html", "guide/configure.html", "guide/configure-advanced.html", "guide/extra.html"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Order = %v, want %v", got, want)
+	}
+}
+
+func TestOrdererSections(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"_meta.json":                `{"tutorials": "Learn", "reference": "API Reference"}`,
+		"guides/_category_.json":    `{"label": "Guides", "position": 5}`,
+		"reference/_category_.json": `{"label": "Reference Docs"}`,
+	})
+	firstIndex := map[string]int{"changelog": 0, "reference": 1, "guides": 2, "tutorials": 3, "blog": 4}
+
+	// _meta.json positions (0, 1) and _category_.json positions (5) order
+	// the sections, unlisted sections follow alphabetically
+	o := newOrderer(&config.Config{}, dir, false, nil)
+	if got, want := o.sectionOrder(firstIndex), []string{"tutorials", "reference", "guides", "blog", "changelog"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sectionOrder = %v, want %v", got, want)
+	}
+
+	// Configured order comes first
+	o = newOrderer(&config.Config{SectionOrder: []string{"changelog", "guides"}}, dir, false, nil)
+	if got, want := o.sectionOrder(firstIndex), []string{"changelog", "guides", "tutorials", "reference", "blog"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sectionOrder with config = %v, want %v", got, want)
+	}
+
+	// Sitemap order breaks the remaining ties
+	o = newOrderer(&config.Config{}, dir, true, nil)
+	if got, want := o.sectionOrder(firstIndex), []string{"tutorials", "reference", "guides", "changelog", "blog"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sectionOrder with sitemap = %v, want %v", got, want)
+	}
+
+	// _category_.json labels win over _meta.json titles of the parent
+	titles := o.sectionTitles([]string{"tutorials", "reference", "guides", "blog"})
+	want := map[string]string{"tutorials": "Learn", "reference": "Reference Docs", "guides": "Guides"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("sectionTitles = %v, want %v", titles, want)
+	}
+}
+
+func TestOrdererLocale(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"api/_category_.json":      `{"label": "API", "position": 1}`,
+		"guide/_category_.json":    `{"label": "Guides", "position": 2}`,
+		"ja/guide/_category_.json": `{"label": "ガイド", "position": 0}`,
+		"ja/guide/_meta.json":      `{"setup": "", "intro": ""}`,
+	})
+	o := newOrderer(&config.Config{PageOrder: []string{"guide/faq.html"}}, dir, false, nil)
+	firstIndex := map[string]int{"api": 0, "guide": 1}
+
+	if got, want := o.sectionOrder(firstIndex), []string{"api", "guide"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sectionOrder = %v, want %v", got, want)
+	}
+
+	// The locale's own files win, directories without any use the root's
+	ja := o.forLocale("ja")
+	if got, want := ja.sectionOrder(firstIndex), []string{"guide", "api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ja sectionOrder = %v, want %v", got, want)
+	}
+	titles := ja.sectionTitles([]string{"guide", "api"})
+	if want := map[string]string{"guide": "ガイド", "api": "API"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("ja sectionTitles = %v, want %v", titles, want)
+	}
+
+	// Pages are ranked by their path below the locale prefix
+	rank := o.rankPage("ja/guide/faq.html", "guide/faq.html", nil, 0)
+	if rank.configPos != 0 {
+		t.Errorf("configPos = %d, want 0", rank.configPos)
+	}
+	rank = o.rankPage("ja/guide/intro.html", "guide/intro.html", nil, 0)
+	if !rank.hasWeight || rank.weight != 1 {
+		t.Errorf("weight = %v, %v; want 1 from ja/guide/_meta.json", rank.weight, rank.hasWeight)
+	}
+}
@@ -0,0 +1,282 @@
+// Package chunk splits extracted pages into heading-aligned chunks for
+// retrieval-augmented generation
+package chunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// Defaults of Options
+const (
+	DefaultTargetTokens  = 512
+	DefaultOverlapTokens = 64
+)
+
+// Options controls the size of chunks and the section titles written
+type Options struct {
+	TargetTokens  int               // Estimated tokens per chunk (0 = DefaultTargetTokens)
+	OverlapTokens int               // Trailing tokens of a chunk repeated at the start of the next one within a section
+	SectionTitles map[string]string // Display titles of section keys, as in formatter.FormatOptions
+}
+
+// Chunk is a piece of a page written as one JSON line
+type Chunk struct {
+	ID       string   `json:"id"`                 // Page URL and chunk number, e.g. "/guide/install:2"
+	URL      string   `json:"url"`                // Page URL with the anchor of the nearest heading
+	Title    string   `json:"title"`              // Page title
+	Section  string   `json:"section"`            // Display title of the page's section, as in llms.txt
+	Optional bool     `json:"optional,omitempty"` // Page is listed under Optional in llms.txt
+	Locale   string   `json:"locale,omitempty"`   // Locale of the page
+	Headings []string `json:"headings,omitempty"` // Heading path from the page down to the chunk
+	Text     string   `json:"text"`               // Markdown text of the chunk
+	Tokens   int      `json:"tokens"`             // Estimated token count of Text
+}
+
+// headingLine matches a Markdown ATX heading
+var headingLine = regexp.MustCompile(`^(#{1,6})[ \t]+(.+?)[ \t]*#*[ \t]*$`)
+
+// inlineLink matches Markdown links and images in heading text
+var inlineLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// part is the text under one heading
+type part struct {
+	headings []string
+	anchor   string
+	blocks   []string
+}
+
+// Split cuts the body of a page at its headings and splits parts larger than
+// TargetTokens between blocks, repeating up to OverlapTokens of trailing blocks
+func Split(content formatter.ExtractedContent, opts Options) []Chunk {
+	if opts.TargetTokens <= 0 {
+		opts.TargetTokens = DefaultTargetTokens
+	}
+
+	var chunks []Chunk
+	for _, p := range splitParts(content.TextContent, content.Headings) {
+		for _, text := range pack(p.blocks, opts) {
+			url := content.URL
+			if p.anchor != "" {
+				url += "#" + p.anchor
+			}
+			chunks = append(chunks, Chunk{
+				ID:       fmt.Sprintf("%s:%d", content.URL, len(chunks)+1),
+				URL:      url,
+				Title:    content.Title,
+				Section:  formatter.SectionTitle(content.Section, opts.SectionTitles),
+				Optional: content.Optional,
+				Locale:   content.Locale,
+				Headings: append([]string{content.Title}, p.headings...),
+				Text:     text,
+				Tokens:   utils.EstimateTokens(text),
+			})
+		}
+	}
+	return chunks
+}
+
+// WriteJSONL writes the chunks of all pages as JSON lines
+func WriteJSONL(w io.Writer, contents []formatter.ExtractedContent, opts Options) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, content := range contents {
+		for _, c := range Split(content, opts) {
+			if err := enc.Encode(c); err != nil {
+				return fmt.Errorf("error writing chunk %s: %w", c.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// splitParts splits a Markdown body into blocks grouped by heading. Headings
+// inside fenced code are ignored and parts without text are dropped. Anchors
+// are taken from the extracted heading with the same text, and derived from
+// the heading text when there is none.
+func splitParts(body string, extracted []formatter.Heading) []part {
+	var parts []part
+	next := 0          // First extracted heading not matched yet
+	var stack []string // Heading texts by level - 1
+	current := part{}
+	var block []string
+	fence := ""
+
+	flushBlock := func() {
+		if text := strings.TrimSpace(strings.Join(block, "\n")); text != "" {
+			current.blocks = append(current.blocks, text)
+		}
+		block = nil
+	}
+	flushPart := func() {
+		flushBlock()
+		if len(current.blocks) > 0 && !(len(current.blocks) == 1 && headingLine.MatchString(current.blocks[0])) {
+			parts = append(parts, current)
+		}
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			block = append(block, line)
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			block = append(block, line)
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+		case headingLine.MatchString(line):
+			flushPart()
+			m := headingLine.FindStringSubmatch(line)
+			level := len(m[1])
+			for len(stack) < level {
+				stack = append(stack, "")
+			}
+			stack = append(stack[:level-1], m[2])
+			anchor := Anchor(m[2])
+			// Extracted headings also include headings that are not on a line
+			// of their own, such as those in blockquotes, so they are matched
+			// by text, in order
+			key := headingKey(m[2])
+			for i := next; i < len(extracted); i++ {
+				if headingKey(extracted[i].Text) == key {
+					if extracted[i].ID != "" {
+						anchor = extracted[i].ID
+					}
+					next = i + 1
+					break
+				}
+			}
+			current = part{headings: nonEmpty(stack), anchor: anchor, blocks: []string{line}}
+		case trimmed == "":
+			flushBlock()
+		default:
+			block = append(block, line)
+		}
+	}
+	flushPart()
+	return parts
+}
+
+// pack groups blocks into texts of about opts.TargetTokens, starting each
+// text after the first with the trailing blocks of the previous one
+func pack(blocks []string, opts Options) []string {
+	var texts []string
+	var current []string
+	tokens := 0
+	for _, block := range splitLarge(blocks, opts.TargetTokens) {
+		t := utils.EstimateTokens(block)
+		if len(current) > 0 && tokens+t > opts.TargetTokens {
+			texts = append(texts, strings.Join(current, "\n\n"))
+			current, tokens = overlap(current, opts.OverlapTokens)
+			if tokens+t > opts.TargetTokens {
+				current, tokens = nil, 0
+			}
+		}
+		current = append(current, block)
+		tokens += t
+	}
+	if len(current) > 0 {
+		texts = append(texts, strings.Join(current, "\n\n"))
+	}
+	return texts
+}
+
+// overlap returns the trailing blocks fitting in maxTokens and their token count
+func overlap(blocks []string, maxTokens int) ([]string, int) {
+	tokens := 0
+	i := len(blocks)
+	for i > 0 {
+		t := utils.EstimateTokens(blocks[i-1])
+		if tokens+t > maxTokens {
+			break
+		}
+		tokens += t
+		i--
+	}
+	return append([]string(nil), blocks[i:]...), tokens
+}
+
+// splitLarge splits blocks larger than maxTokens at line boundaries. Pieces
+// of a fenced code block are fenced again.
+func splitLarge(blocks []string, maxTokens int) []string {
+	var out []string
+	for _, block := range blocks {
+		if utils.EstimateTokens(block) <= maxTokens {
+			out = append(out, block)
+			continue
+		}
+
+		lines := strings.Split(block, "\n")
+		opener, closer := "", ""
+		if first := strings.TrimSpace(lines[0]); len(lines) > 1 && (strings.HasPrefix(first, "```") || strings.HasPrefix(first, "~~~")) {
+			opener = lines[0]
+			closer = first[:len(first)-len(strings.TrimLeft(first, first[:1]))]
+			lines = lines[1:]
+			if strings.TrimSpace(lines[len(lines)-1]) == closer {
+				lines = lines[:len(lines)-1]
+			}
+		}
+		wrap := func(piece []string) string {
+			if opener == "" {
+				return strings.Join(piece, "\n")
+			}
+			return opener + "\n" + strings.Join(piece, "\n") + "\n" + closer
+		}
+
+		var piece []string
+		tokens := utils.EstimateTokens(opener + closer)
+		budget := tokens
+		for _, line := range lines {
+			t := utils.EstimateTokens(line) + 1
+			if len(piece) > 0 && tokens+t > maxTokens {
+				out = append(out, wrap(piece))
+				piece, tokens = nil, budget
+			}
+			piece = append(piece, line)
+			tokens += t
+		}
+		out = append(out, wrap(piece))
+	}
+	return out
+}
+
+// nonEmpty returns the non-empty entries of headings
+func nonEmpty(headings []string) []string {
+	var out []string
+	for _, h := range headings {
+		if h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// headingKey returns the comparable form of a heading, in Markdown or plain
+// text: its anchor with links reduced to their text
+func headingKey(text string) string {
+	return Anchor(inlineLink.ReplaceAllString(text, "$1"))
+}
+
+// Anchor returns the GitHub-style anchor of a heading text: lower-cased, with
+// punctuation removed and spaces replaced by hyphens
+func Anchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,130 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+func TestSplit(t *testing.T) {
+	content := formatter.ExtractedContent{
+		URL:      "/guide/install",
+		Title:    "Install",
+		Section:  "guide",
+		Headings: []formatter.Heading{{Level: 2, Text: "Linux", ID: "linux"}, {Level: 3, Text: "Debian & Ubuntu", ID: "debian"}},
+		TextContent: "Pick a method below.\n\n" +
+			"## Linux\n\n" +
+			"### Debian & Ubuntu\n\n" +
+			"Use apt.\n\n```sh\n# not a heading\napt install tool\n```\n\n" +
+			"## macOS\n\n" +
+			"## Windows\n\n" +
+			strings.Repeat("Download the installer. ", 10) + "\n\n" +
+			strings.Repeat("Run the installer. ", 10) + "\n\n" +
+			strings.Repeat("Restart the shell. ", 10),
+	}
+
+	chunks := Split(content, Options{TargetTokens: 120, OverlapTokens: 60})
+
+	want := []struct {
+		url      string
+		headings string
+		prefix   string
+	}{
+		{"/guide/install", "Install", "Pick a method below."},
+		{"/guide/install#debian", "Install › Linux › Debian & Ubuntu", "### Debian & Ubuntu\n\nUse apt.\n\n```sh\n# not a heading"},
+		{"/guide/install#windows", "Install › Windows", "## Windows\n\nDownload"},
+		{"/guide/install#windows", "Install › Windows", "Run the installer."},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(chunks), len(want), chunks)
+	}
+	for i, w := range want {
+		c := chunks[i]
+		if c.URL != w.url || strings.Join(c.Headings, " › ") != w.headings || !strings.HasPrefix(c.Text, w.prefix) {
+			t.Errorf("chunk %d = %+v, want url %s, headings %s, text starting with %q", i, c, w.url, w.headings, w.prefix)
+		}
+		if c.Section != "Guide" || c.Title != "Install" || c.Tokens == 0 {
+			t.Errorf("chunk %d metadata = %+v", i, c)
+		}
+	}
+	if chunks[3].ID != "/guide/install:4" {
+		t.Errorf("chunks[3].ID = %q", chunks[3].ID)
+	}
+	// The last chunk repeats the final block of the previous one
+	if !strings.HasSuffix(chunks[2].Text, "Run the installer.") || !strings.HasSuffix(chunks[3].Text, "Restart the shell.") {
+		t.Errorf("chunks 2 and 3 should overlap by one block:\n%q\n%q", chunks[2].Text, chunks[3].Text)
+	}
+}
+
+func TestSplitAnchorsByText(t *testing.T) {
+	// Headings inside blockquotes, such as admonitions, are extracted but not
+	// rendered on a line of their own
+	content := formatter.ExtractedContent{
+		URL:   "/guide/setup",
+		Title: "Setup",
+		Headings: []formatter.Heading{
+			{Level: 2, Text: "Careful", ID: "careful"},
+			{Level: 2, Text: "Requirements", ID: "requirements-1"},
+			{Level: 2, Text: "Use tool with Docker", ID: "docker"},
+			{Level: 2, Text: "No anchor"},
+		},
+		TextContent: "> **Warning:**\n>\n> ## Careful\n>\n> Back up first.\n\n" +
+			"## Requirements\n\nA shell.\n\n" +
+			"## Use `tool` with [Docker](https://docker.com)\n\nRun the image.\n\n" +
+			"## No anchor\n\nText.\n\n" +
+			"## Not extracted\n\nMore text.",
+	}
+
+	var urls []string
+	for _, c := range Split(content, Options{}) {
+		urls = append(urls, c.URL)
+	}
+	want := []string{
+		"/guide/setup",
+		"/guide/setup#requirements-1",
+		"/guide/setup#docker",
+		"/guide/setup#no-anchor",
+		"/guide/setup#not-extracted",
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("URLs = %v, want %v", urls, want)
+	}
+}
+
+func TestSplitLargeCodeBlock(t *testing.T) {
+	code := "```go\n" + strings.Repeat("fmt.Println(\"hello, world\")\n", 40) + "```"
+	chunks := Split(formatter.ExtractedContent{URL: "/a", Title: "A", TextContent: code}, Options{TargetTokens: 100})
+	if len(chunks) < 3 {
+		t.Fatalf("got %d chunks, want the code block split", len(chunks))
+	}
+	for _, c := range chunks {
+		if !strings.HasPrefix(c.Text, "```go\n") || !strings.HasSuffix(c.Text, "\n```") {
+			t.Errorf("chunk is not fenced: %q", c.Text)
+		}
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	contents := []formatter.ExtractedContent{
+		{URL: "/a", Title: "A", Locale: "ja", TextContent: "日本語の本文です。"},
+		{URL: "/b", Title: "B", Section: "guides/advanced", Optional: true, TextContent: "<b>Body</b>"},
+	}
+	if err := WriteJSONL(&buf, contents, Options{SectionTitles: map[string]string{"guides": "User Guides"}}); err != nil {
+		t.Fatalf("WriteJSONL() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "<b>Body</b>") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	var c Chunk
+	if err := json.Unmarshal([]byte(lines[0]), &c); err != nil || c.Locale != "ja" || c.Text != "日本語の本文です。" {
+		t.Errorf("first line = %s (%v)", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &c); err != nil || c.Section != "User Guides › Advanced" || !c.Optional {
+		t.Errorf("second line = %s (%v)", lines[1], err)
+	}
+}
@@ -0,0 +1,127 @@
+// Package config loads the optional JSON configuration file of llmstxt-gen
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// Config holds settings that are too structured for command-line flags
+type Config struct {
+	// SectionDepth is the number of leading directories used as the section
+	// key, e.g. 2 turns "guides/advanced/x.html" into "guides/advanced"
+	SectionDepth int `json:"sectionDepth"`
+	// Sections maps path prefixes or globs to sections; the first match wins
+	Sections []SectionRule `json:"sections"`
+	// SectionTitles overrides the display title of section keys,
+	// e.g. {"faq": "FAQ", "guides/advanced": "Advanced Guides"}
+	SectionTitles map[string]string `json:"sectionTitles"`
+	// SectionOrder lists section keys in reading order
+	SectionOrder []string `json:"sectionOrder"`
+	// PageOrder lists path prefixes or globs relative to the HTML directory in
+	// reading order; pages matching an earlier entry come first
+	PageOrder []string `json:"pageOrder"`
+	// Locales holds the localized project name and summary of each locale's
+	// output, keyed by locale code, e.g. {"ja": {"projectName": "..."}}
+	Locales map[string]LocaleConfig `json:"locales"`
+}
+
+// LocaleConfig overrides the header of the output of one locale
+type LocaleConfig struct {
+	ProjectName string `json:"projectName"`
+	Summary     string `json:"summary"`
+}
+
+// SectionRule assigns pages under a path prefix or glob to a section
+type SectionRule struct {
+	Match   string `json:"match"`   // Path prefix ("api/") or glob ("docs/**/api-*.html") relative to the HTML directory
+	Section string `json:"section"` // Section key (defaults to Title)
+	Title   string `json:"title"`   // Display title of the section
+}
+
+// Load reads a configuration file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	for i, rule := range cfg.Sections {
+		if rule.Match == "" {
+			return nil, fmt.Errorf("section rule %d in %s has no match", i, path)
+		}
+		if rule.Key() == "" {
+			return nil, fmt.Errorf("section rule %q in %s needs a section or title", rule.Match, path)
+		}
+	}
+	return &cfg, nil
+}
+
+// Key returns the section key assigned by the rule
+func (r SectionRule) Key() string {
+	if r.Section != "" {
+		return r.Section
+	}
+	return r.Title
+}
+
+// Matches reports whether the rule applies to a path relative to the HTML directory
+func (r SectionRule) Matches(relPath string) bool {
+	return MatchPath(r.Match, relPath)
+}
+
+// MatchPath reports whether a path relative to the HTML directory matches a
+// pattern, which is either a glob or a plain path prefix
+func MatchPath(pattern, relPath string) bool {
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "/")
+	if strings.ContainsAny(pattern, "*?[") {
+		return utils.MatchGlob(pattern, relPath)
+	}
+	prefix := strings.Trim(pattern, "/")
+	return relPath == prefix || strings.HasPrefix(relPath, prefix+"/")
+}
+
+// PagePosition returns the index of the first PageOrder entry matching relPath,
+// or -1 when no entry matches
+func (c *Config) PagePosition(relPath string) int {
+	for i, entry := range c.PageOrder {
+		if MatchPath(entry, relPath) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SectionFor returns the first section rule matching relPath, or nil
+func (c *Config) SectionFor(relPath string) *SectionRule {
+	for i := range c.Sections {
+		if c.Sections[i].Matches(relPath) {
+			return &c.Sections[i]
+		}
+	}
+	return nil
+}
+
+// Titles returns the display titles of section keys, combining SectionTitles
+// with the titles given by section rules
+func (c *Config) Titles() map[string]string {
+	titles := make(map[string]string)
+	for _, rule := range c.Sections {
+		if rule.Title != "" {
+			titles[rule.Key()] = rule.Title
+		}
+	}
+	for key, title := range c.SectionTitles {
+		titles[key] = title
+	}
+	return titles
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "llmstxt.json")
+	data := `{
+		"sectionDepth": 2,
+		"sections": [
+			{"match": "reference/api", "section": "api", "title": "API Reference"},
+			{"match": "**/changelog-*.html", "title": "Changelog"}
+		],
+		"sectionTitles": {"faq": "FAQ"},
+		"locales": {"ja": {"projectName": "例のドキュメント", "summary": "例の概要"}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.SectionDepth != 2 {
+		t.Errorf("SectionDepth = %d, want 2", cfg.SectionDepth)
+	}
+
+	tests := []struct {
+		relPath string
+		want    string
+	}{
+		{"reference/api/users.html", "api"},
+		{"reference/api.html", ""},
+		{"reference/apis/users.html", ""},
+		{"news/2024/changelog-1.html", "Changelog"},
+		{"guide/setup.html", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if rule := cfg.SectionFor(tt.relPath); rule != nil {
+			got = rule.Key()
+		}
+		if got != tt.want {
+			t.Errorf("SectionFor(%q) = %q, want %q", tt.relPath, got, tt.want)
+		}
+	}
+
+	titles := cfg.Titles()
+	if titles["api"] != "API Reference" || titles["Changelog"] != "Changelog" || titles["faq"] != "FAQ" {
+		t.Errorf("Titles() = %v", titles)
+	}
+	if ja := cfg.Locales["ja"]; ja.ProjectName != "例のドキュメント" || ja.Summary != "例の概要" {
+		t.Errorf("Locales[ja] = %+v", ja)
+	}
+}
+
+func TestLoadInvalidRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "llmstxt.json")
+	if err := os.WriteFile(path, []byte(`{"sections": [{"match": "api/"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Errorf("Expected an error for a rule without section or title")
+	}
+}
@@ -0,0 +1,137 @@
+package dedup
+
+import (
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// Boilerplate is a block removed from the pages it appeared on
+type Boilerplate struct {
+	Text  string // Block as it appeared on the first page
+	Pages int    // Number of pages containing the block
+}
+
+// RemoveBoilerplate strips Markdown blocks, such as "Was this page helpful?"
+// or "Edit on GitHub" paragraphs, that appear on more than fraction of the
+// pages (and on at least two). Code blocks and headings are never removed.
+// It returns the updated contents and the removed blocks in order of first appearance.
+func RemoveBoilerplate(contents []formatter.ExtractedContent, fraction float64) ([]formatter.ExtractedContent, []Boilerplate) {
+	if fraction <= 0 || len(contents) < 2 {
+		return contents, nil
+	}
+
+	pageBlocks := make([][]string, len(contents))
+	counts := make(map[string]int)
+	var order []string
+	first := make(map[string]string)
+	for i, content := range contents {
+		pageBlocks[i] = splitBlocks(content.TextContent)
+		seen := make(map[string]bool)
+		for _, block := range pageBlocks[i] {
+			key := blockKey(block)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if counts[key] == 0 {
+				order = append(order, key)
+				first[key] = block
+			}
+			counts[key]++
+		}
+	}
+
+	limit := fraction * float64(len(contents))
+	boilerplate := make(map[string]bool)
+	var removed []Boilerplate
+	for _, key := range order {
+		if counts[key] >= 2 && float64(counts[key]) > limit {
+			boilerplate[key] = true
+			removed = append(removed, Boilerplate{Text: first[key], Pages: counts[key]})
+		}
+	}
+	if len(boilerplate) == 0 {
+		return contents, nil
+	}
+
+	result := make([]formatter.ExtractedContent, len(contents))
+	for i, content := range contents {
+		var kept []string
+		for _, block := range pageBlocks[i] {
+			if !boilerplate[blockKey(block)] {
+				kept = append(kept, block)
+			}
+		}
+		content.TextContent = strings.Join(kept, "\n\n")
+		result[i] = content
+	}
+	return result, removed
+}
+
+// StripBoilerplate removes the given blocks, as returned by RemoveBoilerplate,
+// from a Markdown body
+func StripBoilerplate(body string, blocks []Boilerplate) string {
+	if len(blocks) == 0 {
+		return body
+	}
+	boilerplate := make(map[string]bool, len(blocks))
+	for _, b := range blocks {
+		boilerplate[blockKey(b.Text)] = true
+	}
+	var kept []string
+	for _, block := range splitBlocks(body) {
+		if !boilerplate[blockKey(block)] {
+			kept = append(kept, block)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
+
+// blockKey returns the comparable form of a block, or "" for blocks that are
+// never treated as boilerplate
+func blockKey(block string) string {
+	if isFence(block) || strings.HasPrefix(block, "#") {
+		return ""
+	}
+	return strings.Join(strings.Fields(block), " ")
+}
+
+// splitBlocks splits a Markdown body on blank lines, keeping fenced code
+// blocks, which may contain blank lines, in one piece
+func splitBlocks(body string) []string {
+	var blocks []string
+	var current []string
+	fence := ""
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			current = append(current, line)
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		case isFence(trimmed):
+			current = append(current, line)
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+		case trimmed == "":
+			flush()
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// isFence reports whether s starts with a code fence
+func isFence(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "```") || strings.HasPrefix(s, "~~~")
+}
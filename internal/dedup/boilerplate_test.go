@@ -0,0 +1,48 @@
+package dedup
+
+import (
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+func TestRemoveBoilerplate(t *testing.T) {
+	const footer = "Was this page helpful?\n\n[Edit on GitHub](https://github.com/example/docs)"
+	contents := []formatter.ExtractedContent{
+		{URL: "/a", TextContent: "Install the tool.\n\n```sh\nmake\n\nmake install\n```\n\n" + footer},
+		{URL: "/b", TextContent: "## Usage\n\nRun the tool.\n\n```sh\nmake\n\nmake install\n```\n\n" + footer},
+		{URL: "/c", TextContent: "## Usage\n\nConfigure the tool.\n\nWas  this page\nhelpful?"},
+		{URL: "/d", TextContent: "Release notes."},
+	}
+
+	got, removed := RemoveBoilerplate(contents, 0.4)
+
+	want := []string{
+		"Install the tool.\n\n```sh\nmake\n\nmake install\n```",
+		"## Usage\n\nRun the tool.\n\n```sh\nmake\n\nmake install\n```",
+		"## Usage\n\nConfigure the tool.",
+		"Release notes.",
+	}
+	for i := range want {
+		if got[i].TextContent != want[i] {
+			t.Errorf("page %s =\n%s\nwant\n%s", got[i].URL, got[i].TextContent, want[i])
+		}
+	}
+	if len(removed) != 2 || removed[0].Text != "Was this page helpful?" || removed[0].Pages != 3 || removed[1].Pages != 2 {
+		t.Errorf("removed = %+v, want the helpful prompt and the edit link", removed)
+	}
+	if contents[0].TextContent == got[0].TextContent {
+		t.Error("RemoveBoilerplate modified its input")
+	}
+}
+
+func TestStripBoilerplate(t *testing.T) {
+	blocks := []Boilerplate{{Text: "Was this page helpful?", Pages: 3}}
+	body := "Intro.\n\nWas this\npage helpful?\n\n```\nWas this page helpful?\n```"
+	if got, want := StripBoilerplate(body, blocks), "Intro.\n\n```\nWas this page helpful?\n```"; got != want {
+		t.Errorf("StripBoilerplate = %q, want %q", got, want)
+	}
+	if got := StripBoilerplate(body, nil); got != body {
+		t.Errorf("StripBoilerplate without blocks = %q", got)
+	}
+}
@@ -0,0 +1,251 @@
+// Package dedup finds exact and near-duplicate pages among extracted contents
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/timakin/llmstxt-gen/internal/extractor"
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// DefaultMaxDistance is the default number of differing simhash bits up to
+// which two pages are considered near-duplicates
+const DefaultMaxDistance = 3
+
+// shingleSize is the number of words in each shingle
+const shingleSize = 3
+
+// minShingles is the number of shingles below which a page is too short for
+// near-duplicate detection; such pages are only compared exactly
+const minShingles = 8
+
+// Options controls duplicate detection
+type Options struct {
+	// MaxDistance is the simhash Hamming distance up to which pages are
+	// near-duplicates (0 = exact duplicates only, negative = DefaultMaxDistance)
+	MaxDistance int
+	// Priority ranks pages of a cluster, e.g. by sitemap priority; the page
+	// with the highest priority is kept when no page is canonical (optional)
+	Priority func(content formatter.ExtractedContent) float64
+}
+
+// Duplicate reports a page dropped in favor of another one
+type Duplicate struct {
+	Dropped  formatter.ExtractedContent
+	Kept     formatter.ExtractedContent
+	Exact    bool // Texts are identical after normalization
+	Distance int  // Simhash Hamming distance, 0 for exact duplicates
+}
+
+// fingerprint is the comparable form of a page
+type fingerprint struct {
+	text    string
+	simhash uint64
+	shingle bool // Long enough for near-duplicate detection
+}
+
+// Remove groups duplicate pages and keeps one page per group, preferring the
+// page that others name as canonical, then the page whose canonical link points
+// to itself, then the highest Priority, then the first page. Pages with an
+// empty body are never considered duplicates. The order of kept pages is preserved.
+func Remove(contents []formatter.ExtractedContent, opts Options) ([]formatter.ExtractedContent, []Duplicate) {
+	if opts.MaxDistance < 0 {
+		opts.MaxDistance = DefaultMaxDistance
+	}
+
+	prints := make([]fingerprint, len(contents))
+	for i, content := range contents {
+		prints[i] = fingerprintOf(content.TextContent)
+	}
+
+	// Union pages into clusters
+	parent := make([]int, len(contents))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range contents {
+		if prints[i].text == "" {
+			continue
+		}
+		for j := i + 1; j < len(contents); j++ {
+			if prints[j].text == "" {
+				continue
+			}
+			if _, ok := compare(prints[i], prints[j], opts.MaxDistance); ok {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	clusters := make(map[int][]int)
+	for i := range contents {
+		root := find(i)
+		clusters[root] = append(clusters[root], i)
+	}
+
+	drop := make(map[int]int) // Dropped index → kept index
+	for _, members := range clusters {
+		if len(members) < 2 {
+			continue
+		}
+		keep := preferred(contents, members, opts)
+		for _, i := range members {
+			if i != keep {
+				drop[i] = keep
+			}
+		}
+	}
+
+	var kept []formatter.ExtractedContent
+	var dropped []Duplicate
+	for i, content := range contents {
+		k, ok := drop[i]
+		if !ok {
+			kept = append(kept, content)
+			continue
+		}
+		distance, _ := compare(prints[i], prints[k], opts.MaxDistance)
+		dropped = append(dropped, Duplicate{
+			Dropped:  content,
+			Kept:     contents[k],
+			Exact:    prints[i].text == prints[k].text,
+			Distance: distance,
+		})
+	}
+	return kept, dropped
+}
+
+// compare returns the simhash distance of two pages and whether they are duplicates
+func compare(a, b fingerprint, maxDistance int) (int, bool) {
+	if a.text == b.text {
+		return 0, true
+	}
+	distance := bits.OnesCount64(a.simhash ^ b.simhash)
+	return distance, maxDistance > 0 && a.shingle && b.shingle && distance <= maxDistance
+}
+
+// preferred returns the index of the page to keep among cluster members
+func preferred(contents []formatter.ExtractedContent, members []int, opts Options) int {
+	named := make(map[string]bool) // Canonical targets named by members
+	for _, i := range members {
+		if canonical := canonicalPath(contents[i]); canonical != "" {
+			named[canonical] = true
+		}
+	}
+
+	best := members[0]
+	bestScore := score(contents[best], named, opts)
+	for _, i := range members[1:] {
+		if s := score(contents[i], named, opts); s.better(bestScore) {
+			best, bestScore = i, s
+		}
+	}
+	return best
+}
+
+// rank orders the candidates of a cluster
+type rank struct {
+	named     bool // Another member's canonical link points to this page
+	canonical bool // The page's own canonical link points to itself
+	priority  float64
+}
+
+func (r rank) better(o rank) bool {
+	if r.named != o.named {
+		return r.named
+	}
+	if r.canonical != o.canonical {
+		return r.canonical
+	}
+	return r.priority > o.priority
+}
+
+func score(content formatter.ExtractedContent, named map[string]bool, opts Options) rank {
+	self := utils.NormalizeURLPath(content.URL)
+	r := rank{named: named[self], canonical: canonicalPath(content) == self}
+	if opts.Priority != nil {
+		r.priority = opts.Priority(content)
+	}
+	return r
+}
+
+// canonicalPath returns the normalized path of the page's canonical link, or ""
+func canonicalPath(content formatter.ExtractedContent) string {
+	href := content.Metadata[extractor.MetaCanonical]
+	if href == "" {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return utils.NormalizeURLPath(u.Path)
+}
+
+// fingerprintOf normalizes a page body and computes its simhash over word shingles
+func fingerprintOf(body string) fingerprint {
+	words := splitWords(body)
+	fp := fingerprint{text: strings.Join(words, " ")}
+	if len(words) < shingleSize+minShingles-1 {
+		return fp
+	}
+	fp.shingle = true
+
+	var weights [64]int
+	for i := 0; i+shingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+shingleSize], " ")))
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+	for b, w := range weights {
+		if w > 0 {
+			fp.simhash |= 1 << b
+		}
+	}
+	return fp
+}
+
+// splitWords splits text into lower-cased words, ignoring punctuation and Markdown
+// syntax. CJK characters count as one word each since the text has no spaces.
+func splitWords(text string) []string {
+	var out []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			out = append(out, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case utils.IsCJK(r):
+			flush()
+			out = append(out, string(r))
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
@@ -0,0 +1,73 @@
+package dedup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+const guide = "Install the command line tool with the package manager of your platform. " +
+	"Then run the init command in the root of your project to create a configuration file. " +
+	"The configuration file lists the directories to scan and the output location. " +
+	"Run the build command to generate the documentation index. " +
+	"Pages are grouped into sections by directory, and each section lists its pages with a short note. " +
+	"Code samples are kept as fenced blocks with their language, tables become pipe tables, and images keep their alt text. " +
+	"When the sitemap is given, only the pages it lists are processed, in the order of the sitemap. " +
+	"Links between pages are resolved against the URL of the page they appear on. " +
+	"Use the check mode in continuous integration to make sure the committed index is up to date with the documentation."
+
+func TestRemove(t *testing.T) {
+	contents := []formatter.ExtractedContent{
+		{URL: "/v1/guide", TextContent: guide},
+		{URL: "/v2/guide", TextContent: "## Guide\n\n" + guide, Metadata: map[string]string{"canonical": "https://docs.example.com/guide"}},
+		{URL: "/guide", TextContent: strings.Replace(guide, "Then run", "Next, run", 1)},
+		{URL: "/print/guide", TextContent: guide + " Printed from the web."},
+		{URL: "/faq", TextContent: "A different page about frequently asked questions, billing and accounts, and nothing else at all."},
+		{URL: "/empty-a"},
+		{URL: "/empty-b"},
+	}
+
+	kept, dropped := Remove(contents, Options{MaxDistance: DefaultMaxDistance})
+
+	var urls []string
+	for _, c := range kept {
+		urls = append(urls, c.URL)
+	}
+	if got, want := strings.Join(urls, ","), "/guide,/faq,/empty-a,/empty-b"; got != want {
+		t.Errorf("kept = %s, want %s", got, want)
+	}
+	if len(dropped) != 3 {
+		t.Fatalf("dropped %d pages, want 3: %+v", len(dropped), dropped)
+	}
+	for _, d := range dropped {
+		if d.Kept.URL != "/guide" {
+			t.Errorf("%s kept %s, want /guide", d.Dropped.URL, d.Kept.URL)
+		}
+	}
+	if dropped[0].Exact || dropped[0].Distance > DefaultMaxDistance {
+		t.Errorf("dropped[0] = %+v, want a near-duplicate", dropped[0])
+	}
+}
+
+func TestRemoveExactOnly(t *testing.T) {
+	contents := []formatter.ExtractedContent{
+		{URL: "/a", TextContent: guide},
+		{URL: "/b", TextContent: guide + " Printed from the web."},
+		{URL: "/c", TextContent: guide},
+	}
+	priority := func(c formatter.ExtractedContent) float64 {
+		if c.URL == "/c" {
+			return 1
+		}
+		return 0.5
+	}
+
+	kept, dropped := Remove(contents, Options{Priority: priority})
+	if len(kept) != 2 || kept[0].URL != "/b" || kept[1].URL != "/c" {
+		t.Errorf("kept = %+v, want /b and /c", kept)
+	}
+	if len(dropped) != 1 || dropped[0].Dropped.URL != "/a" || !dropped[0].Exact {
+		t.Errorf("dropped = %+v, want exact duplicate /a", dropped)
+	}
+}
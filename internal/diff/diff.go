@@ -0,0 +1,289 @@
+// Package diff compares llms.txt files: it reports how pages changed and writes unified diffs
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+// Page identifies a page of a Document
+type Page struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Section string `json:"section"`
+}
+
+// Rename is a page whose URL changed
+type Rename struct {
+	OldURL string `json:"oldUrl"`
+	NewURL string `json:"newUrl"`
+	Title  string `json:"title"`
+}
+
+// Change is a page whose title, notes or section changed
+type Change struct {
+	URL string `json:"url"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// SectionDelta is the change in estimated tokens of a section
+type SectionDelta struct {
+	Section   string `json:"section"`
+	OldTokens int    `json:"oldTokens"`
+	NewTokens int    `json:"newTokens"`
+	Delta     int    `json:"delta"`
+}
+
+// Report lists the differences between two documents
+type Report struct {
+	Added          []Page         `json:"added"`
+	Removed        []Page         `json:"removed"`
+	Renamed        []Rename       `json:"renamed"`
+	Moved          []Change       `json:"moved"`          // Section titles
+	TitlesChanged  []Change       `json:"titlesChanged"`  // Page titles
+	NotesChanged   []Change       `json:"notesChanged"`   // Notes of link entries
+	DetailsChanged []string       `json:"detailsChanged"` // URLs of pages whose detailed content changed
+	Sections       []SectionDelta `json:"sections"`       // Sections whose token count changed
+}
+
+// entry is a page with its section
+type entry struct {
+	page    formatter.ParsedPage
+	section string
+}
+
+// Compare reports the differences from old to new. Pages are matched by URL;
+// a removed and an added page with the same title and either the same section,
+// details or last URL path segment, or else the same detailed content, are
+// reported as renamed. Equal titles alone, such as "Overview", are too common.
+func Compare(old, new *formatter.Document) *Report {
+	// Empty lists rather than nulls in JSON
+	r := &Report{
+		Added:          []Page{},
+		Removed:        []Page{},
+		Renamed:        []Rename{},
+		Moved:          []Change{},
+		TitlesChanged:  []Change{},
+		NotesChanged:   []Change{},
+		DetailsChanged: []string{},
+	}
+	oldPages, oldOrder := pagesByURL(old)
+	newPages, newOrder := pagesByURL(new)
+
+	var removed, added []entry
+	for _, url := range oldOrder {
+		if _, ok := newPages[url]; !ok {
+			removed = append(removed, oldPages[url])
+		}
+	}
+	for _, url := range newOrder {
+		o, ok := oldPages[url]
+		if !ok {
+			added = append(added, newPages[url])
+			continue
+		}
+		r.compareEntries(url, o, newPages[url])
+	}
+
+	// Pair removed and added pages into renames
+	matched := make(map[int]bool)
+	for _, same := range []func(a, b entry) bool{
+		func(a, b entry) bool {
+			return a.page.Title == b.page.Title && (a.section == b.section || sameDetails(a, b) || urlStem(a.page.URL) == urlStem(b.page.URL))
+		},
+		sameDetails,
+	} {
+		var rest []entry
+		for _, o := range removed {
+			found := false
+			for j, n := range added {
+				if !matched[j] && same(o, n) {
+					matched[j] = true
+					found = true
+					r.Renamed = append(r.Renamed, Rename{OldURL: o.page.URL, NewURL: n.page.URL, Title: n.page.Title})
+					r.compareEntries(n.page.URL, o, n)
+					break
+				}
+			}
+			if !found {
+				rest = append(rest, o)
+			}
+		}
+		removed = rest
+	}
+	for _, o := range removed {
+		r.Removed = append(r.Removed, o.ref())
+	}
+	for j, n := range added {
+		if !matched[j] {
+			r.Added = append(r.Added, n.ref())
+		}
+	}
+
+	r.Sections = sectionDeltas(old, new)
+	return r
+}
+
+// compareEntries records the changes of a page present in both documents
+func (r *Report) compareEntries(url string, o, n entry) {
+	if o.section != n.section {
+		r.Moved = append(r.Moved, Change{URL: url, Old: o.section, New: n.section})
+	}
+	if o.page.Title != n.page.Title {
+		r.TitlesChanged = append(r.TitlesChanged, Change{URL: url, Old: o.page.Title, New: n.page.Title})
+	}
+	if o.page.Notes != n.page.Notes {
+		r.NotesChanged = append(r.NotesChanged, Change{URL: url, Old: o.page.Notes, New: n.page.Notes})
+	}
+	if o.page.Details != n.page.Details {
+		r.DetailsChanged = append(r.DetailsChanged, url)
+	}
+}
+
+// sameDetails reports whether two pages have the same detailed content
+func sameDetails(a, b entry) bool {
+	return a.page.Details != "" && a.page.Details == b.page.Details
+}
+
+// urlStem returns the last path segment of a URL without its extension, e.g.
+// "install" for "/v1/guides/install.html#linux"
+func urlStem(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	base := path.Base(strings.TrimSuffix(url, "/"))
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+func (e entry) ref() Page {
+	return Page{URL: e.page.URL, Title: e.page.Title, Section: e.section}
+}
+
+// pagesByURL indexes the pages of a document, keeping the first entry of each URL
+func pagesByURL(doc *formatter.Document) (map[string]entry, []string) {
+	pages := make(map[string]entry)
+	var order []string
+	for _, s := range doc.Sections {
+		for _, p := range s.Pages {
+			if _, ok := pages[p.URL]; ok {
+				continue
+			}
+			pages[p.URL] = entry{page: p, section: s.Title}
+			order = append(order, p.URL)
+		}
+	}
+	return pages, order
+}
+
+// sectionDeltas returns the token changes of sections, in the order of new
+// followed by sections only found in old
+func sectionDeltas(old, new *formatter.Document) []SectionDelta {
+	oldTokens := make(map[string]int)
+	for _, s := range old.Sections {
+		oldTokens[s.Title] += s.Tokens
+	}
+	newTokens := make(map[string]int)
+	var titles []string
+	for _, s := range new.Sections {
+		if _, ok := newTokens[s.Title]; !ok {
+			titles = append(titles, s.Title)
+		}
+		newTokens[s.Title] += s.Tokens
+	}
+	var gone []string
+	for title := range oldTokens {
+		if _, ok := newTokens[title]; !ok {
+			gone = append(gone, title)
+		}
+	}
+	sort.Strings(gone)
+
+	deltas := []SectionDelta{}
+	for _, title := range append(titles, gone...) {
+		d := SectionDelta{Section: title, OldTokens: oldTokens[title], NewTokens: newTokens[title]}
+		d.Delta = d.NewTokens - d.OldTokens
+		if d.Delta != 0 {
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
+
+// Empty reports whether the documents list the same pages with the same content
+func (r *Report) Empty() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Renamed)+len(r.Moved)+len(r.TitlesChanged)+
+		len(r.NotesChanged)+len(r.DetailsChanged)+len(r.Sections) == 0
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
+
+// WriteText writes the report in a human-readable form
+func (r *Report) WriteText(w io.Writer) error {
+	if r.Empty() {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+
+	var lines []string
+	add := func(format string, args ...any) { lines = append(lines, fmt.Sprintf(format, args...)) }
+	heading := func(title string, n int) {
+		if n > 0 {
+			add("%s (%d):", title, n)
+		}
+	}
+
+	heading("Added pages", len(r.Added))
+	for _, p := range r.Added {
+		add("  + %s %q [%s]", p.URL, p.Title, p.Section)
+	}
+	heading("Removed pages", len(r.Removed))
+	for _, p := range r.Removed {
+		add("  - %s %q [%s]", p.URL, p.Title, p.Section)
+	}
+	heading("Renamed pages", len(r.Renamed))
+	for _, p := range r.Renamed {
+		add("  %s → %s %q", p.OldURL, p.NewURL, p.Title)
+	}
+	heading("Moved pages", len(r.Moved))
+	for _, c := range r.Moved {
+		add("  %s: %s → %s", c.URL, c.Old, c.New)
+	}
+	heading("Changed titles", len(r.TitlesChanged))
+	for _, c := range r.TitlesChanged {
+		add("  %s: %q → %q", c.URL, c.Old, c.New)
+	}
+	heading("Changed notes", len(r.NotesChanged))
+	for _, c := range r.NotesChanged {
+		add("  %s:", c.URL)
+		add("    - %s", c.Old)
+		add("    + %s", c.New)
+	}
+	heading("Changed content", len(r.DetailsChanged))
+	for _, url := range r.DetailsChanged {
+		add("  %s", url)
+	}
+	heading("Section tokens", len(r.Sections))
+	for _, d := range r.Sections {
+		add("  %s: %d → %d (%+d)", d.Section, d.OldTokens, d.NewTokens, d.Delta)
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
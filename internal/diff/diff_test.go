@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/timakin/llmstxt-gen/internal/formatter"
+)
+
+func parse(t *testing.T, text string) *formatter.Document {
+	t.Helper()
+	doc, err := formatter.Parse(text)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return doc
+}
+
+const oldText = `# Docs
+
+> Old summary
+
+## Guides
+
+- [Install](/guides/install): How to install
+- [Setup](/guides/setup): Configure the tool
+- [Legacy](/guides/legacy): Old page
+
+## Reference
+
+- [API](/reference/api): The API
+`
+
+const newText = `# Docs
+
+> New summary
+
+## Guides
+
+- [Installation](/guides/install): How to install the tool
+- [Setup](/guides/configure): Configure the tool
+
+## Reference
+
+- [API](/reference/api): The API
+- [CLI](/reference/cli): Command line options
+`
+
+func TestCompare(t *testing.T) {
+	r := Compare(parse(t, oldText), parse(t, newText))
+
+	if len(r.Added) != 1 || r.Added[0].URL != "/reference/cli" || r.Added[0].Section != "Reference" {
+		t.Errorf("Added = %+v", r.Added)
+	}
+	if len(r.Removed) != 1 || r.Removed[0].URL != "/guides/legacy" {
+		t.Errorf("Removed = %+v", r.Removed)
+	}
+	if len(r.Renamed) != 1 || r.Renamed[0].OldURL != "/guides/setup" || r.Renamed[0].NewURL != "/guides/configure" {
+		t.Errorf("Renamed = %+v", r.Renamed)
+	}
+	if len(r.TitlesChanged) != 1 || r.TitlesChanged[0].New != "Installation" {
+		t.Errorf("TitlesChanged = %+v", r.TitlesChanged)
+	}
+	if len(r.NotesChanged) != 1 || r.NotesChanged[0].URL != "/guides/install" {
+		t.Errorf("NotesChanged = %+v", r.NotesChanged)
+	}
+	if len(r.Moved) != 0 {
+		t.Errorf("Moved = %+v", r.Moved)
+	}
+	if len(r.Sections) != 2 {
+		t.Errorf("Sections = %+v", r.Sections)
+	}
+	for _, d := range r.Sections {
+		if d.Delta != d.NewTokens-d.OldTokens {
+			t.Errorf("Delta of %s = %d", d.Section, d.Delta)
+		}
+	}
+}
+
+func TestCompareMoved(t *testing.T) {
+	old := "# Docs\n\n## A\n\n- [Page](/page)\n"
+	new := "# Docs\n\n## B\n\n- [Page](/page)\n"
+	r := Compare(parse(t, old), parse(t, new))
+	if len(r.Moved) != 1 || r.Moved[0].Old != "A" || r.Moved[0].New != "B" {
+		t.Errorf("Moved = %+v", r.Moved)
+	}
+}
+
+func TestCompareRenames(t *testing.T) {
+	old := "# Docs\n\n## Guides\n\n- [Overview](/guides/intro)\n- [Install](/v1/install)\n"
+	new := "# Docs\n\n## Reference\n\n- [Overview](/reference/summary)\n- [Install](/v2/install)\n"
+	r := Compare(parse(t, old), parse(t, new))
+
+	// Same title in another section under another name is a different page
+	if len(r.Renamed) != 1 || r.Renamed[0].OldURL != "/v1/install" || r.Renamed[0].NewURL != "/v2/install" {
+		t.Errorf("Renamed = %+v", r.Renamed)
+	}
+	if len(r.Removed) != 1 || r.Removed[0].URL != "/guides/intro" || len(r.Added) != 1 || r.Added[0].URL != "/reference/summary" {
+		t.Errorf("Removed = %+v, Added = %+v", r.Removed, r.Added)
+	}
+}
+
+func TestCompareSame(t *testing.T) {
+	r := Compare(parse(t, oldText), parse(t, oldText))
+	if !r.Empty() {
+		t.Errorf("Expected an empty report, got %+v", r)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "No changes" {
+		t.Errorf("WriteText = %q", buf.String())
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	r := Compare(parse(t, oldText), parse(t, newText))
+
+	var text bytes.Buffer
+	if err := r.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Added pages (1):",
+		"+ /reference/cli",
+		"- /guides/legacy",
+		"/guides/setup → /guides/configure",
+		`"Install" → "Installation"`,
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Text report missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(decoded.Added) != 1 || len(decoded.Renamed) != 1 {
+		t.Errorf("Decoded report = %+v", decoded)
+	}
+}
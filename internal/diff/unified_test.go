@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	if got := Unified("a", "b", "same\n", "same\n"); got != "" {
+		t.Errorf("Equal texts: got %q", got)
+	}
+
+	oldText := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	newText := "one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight\nnine\nten\neleven\n"
+	want := `--- llms.txt
++++ llms.txt (generated)
+@@ -2,9 +2,10 @@
+ two
+ three
+ four
+-five
++FIVE
+ six
+ seven
+ eight
+ nine
+ ten
++eleven
+`
+	if got := Unified("llms.txt", "llms.txt (generated)", oldText, newText); got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedSeparateHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 30; i++ {
+		line := strings.Repeat("x", i+1)
+		oldLines = append(oldLines, line)
+		if i == 2 || i == 25 {
+			line += "!"
+		}
+		newLines = append(newLines, line)
+	}
+	got := Unified("a", "b", strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Errorf("Expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,6 +1,6 @@") || !strings.Contains(got, "@@ -23,7 +23,7 @@") {
+		t.Errorf("Unexpected hunk ranges:\n%s", got)
+	}
+}
+
+func TestUnifiedFromEmpty(t *testing.T) {
+	want := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+one\n+two\n"
+	if got := Unified("a", "b", "", "one\ntwo\n"); got != want {
+		t.Errorf("Unified = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedTrailingNewline(t *testing.T) {
+	want := "--- a\n+++ b\n@@ -1,2 +1,2 @@\n one\n-two\n+two\n\\ No newline at end of file\n"
+	if got := Unified("a", "b", "one\ntwo\n", "one\ntwo"); got != want {
+		t.Errorf("Unified = %q, want %q", got, want)
+	}
+	want = "--- a\n+++ b\n@@ -1 +1,2 @@\n-one\n\\ No newline at end of file\n+one\n+two\n"
+	if got := Unified("a", "b", "one", "one\ntwo\n"); got != want {
+		t.Errorf("Unified = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedLargeChange(t *testing.T) {
+	// More edits than maxEditDistance fall back to replacing the changed lines
+	var oldLines, newLines []string
+	for i := 0; i < maxEditDistance; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("old %d", i))
+		newLines = append(newLines, fmt.Sprintf("new %d", i))
+	}
+	oldText := "same\n" + strings.Join(oldLines, "\n") + "\nend\n"
+	newText := "same\n" + strings.Join(newLines, "\n") + "\nend\n"
+
+	got := Unified("a", "b", oldText, newText)
+	if !strings.HasPrefix(got, fmt.Sprintf("--- a\n+++ b\n@@ -1,%d +1,%d @@\n same\n-old 0\n", maxEditDistance+2, maxEditDistance+2)) {
+		t.Errorf("Unexpected start of diff:\n%.200s", got)
+	}
+	if n := strings.Count(got, "\n-old "); n != maxEditDistance {
+		t.Errorf("Expected %d removed lines, got %d", maxEditDistance, n)
+	}
+	if n := strings.Count(got, "\n+new "); n != maxEditDistance {
+		t.Errorf("Expected %d added lines, got %d", maxEditDistance, n)
+	}
+}
@@ -0,0 +1,119 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// admonitionLabels maps callout kinds, as they appear in class names, to the
+// label emitted in the Markdown body
+var admonitionLabels = map[string]string{
+	"note": "Note", "info": "Info", "tip": "Tip", "hint": "Tip", "important": "Important",
+	"warning": "Warning", "attention": "Warning", "caution": "Caution", "danger": "Danger",
+	"error": "Error", "success": "Success", "question": "Question", "example": "Example",
+	"abstract": "Summary", "summary": "Summary", "quote": "Quote", "bug": "Bug",
+	"failure": "Failure", "todo": "Todo", "seealso": "See also",
+}
+
+// admonitionClassPrefixes are stripped from class names to find the callout kind
+var admonitionClassPrefixes = []string{"theme-admonition-", "admonition-", "markdown-alert-"}
+
+// admonition is a callout box such as a note or a warning
+type admonition struct {
+	label string     // Label of the kind, e.g. "Warning"
+	title *html.Node // Element holding the title, nil when absent
+}
+
+// detectAdmonition recognizes callout markup of common documentation themes:
+// Docusaurus (.theme-admonition, .admonition), MkDocs (.admonition and
+// collapsible <details>), GitHub-style alerts (.markdown-alert) and VitePress
+// (.custom-block). It returns nil for other elements.
+func detectAdmonition(n *html.Node) *admonition {
+	if n.Type != html.ElementNode {
+		return nil
+	}
+	switch {
+	case htmlutil.HasClass(n, "admonition"), htmlutil.HasClass(n, "theme-admonition"),
+		htmlutil.HasClass(n, "markdown-alert"), htmlutil.HasClass(n, "custom-block"):
+	case n.DataAtom == atom.Details:
+		// MkDocs collapsible blocks carry the kind as a plain class
+	default:
+		return nil
+	}
+
+	label := admonitionLabel(n)
+	if label == "" {
+		return nil
+	}
+	a := &admonition{label: label}
+	if n.DataAtom == atom.Details {
+		a.title = htmlutil.Find(n, htmlutil.ByTag(atom.Summary))
+	} else {
+		a.title = htmlutil.Find(n, isAdmonitionTitle)
+	}
+	return a
+}
+
+// admonitionLabel returns the label of the first class naming a callout kind
+func admonitionLabel(n *html.Node) string {
+	for _, class := range htmlutil.Classes(n) {
+		kind := strings.ToLower(class)
+		for _, prefix := range admonitionClassPrefixes {
+			kind = strings.TrimPrefix(kind, prefix)
+		}
+		if label, ok := admonitionLabels[kind]; ok {
+			return label
+		}
+	}
+	return ""
+}
+
+// isAdmonitionTitle reports whether n holds the title of a callout. Docusaurus
+// uses CSS module class names such as "admonitionHeading_Gvgb".
+func isAdmonitionTitle(n *html.Node) bool {
+	for _, class := range htmlutil.Classes(n) {
+		switch {
+		case class == "admonition-title", class == "markdown-alert-title", class == "custom-block-title",
+			class == "admonition-heading", strings.HasPrefix(class, "admonitionHeading"):
+			return true
+		}
+	}
+	return false
+}
+
+// markdownBlockStart matches blocks that cannot follow the label on the same line
+var markdownBlockStart = regexp.MustCompile("^(```|[-*+] |\\d+\\. |#|\\||> )")
+
+// renderAdmonition renders a callout as a blockquote starting with its label,
+// e.g. "> **Warning:** ...". A custom title is kept next to the label.
+func (r *renderer) renderAdmonition(n *html.Node, a *admonition) []string {
+	label, titled := "**"+a.label+":**", false
+	if a.title != nil {
+		title := cleanInline(r.renderInlineChildren(a.title))
+		title = strings.TrimSuffix(strings.ReplaceAll(title, "\n", " "), ":")
+		if title != "" && !strings.EqualFold(title, a.label) && !isAdmonitionKind(title) {
+			label, titled = "**"+a.label+": "+title+"**", true
+		}
+		r.skip[a.title] = true
+	}
+
+	blocks := r.renderBlocks(n)
+	if len(blocks) > 0 && !titled && !markdownBlockStart.MatchString(blocks[0]) {
+		blocks[0] = label + " " + blocks[0]
+	} else {
+		blocks = append([]string{label}, blocks...)
+	}
+	return []string{prefixLines(strings.Join(blocks, "\n\n"), "> ")}
+}
+
+// isAdmonitionKind reports whether title is only the name of a callout kind,
+// as Docusaurus and GitHub render the default titles
+func isAdmonitionKind(title string) bool {
+	_, ok := admonitionLabels[strings.ToLower(title)]
+	return ok
+}
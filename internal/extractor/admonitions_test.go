@@ -0,0 +1,52 @@
+package extractor
+
+import "testing"
+
+func TestRenderAdmonitions(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "Docusaurus",
+			html: `<div class="theme-admonition theme-admonition-warning admonition_xJq3 alert alert--warning">
+<div class="admonitionHeading_Gvgb"><span class="admonitionIcon_Rf37"><svg viewBox="0 0 16 16"><path d="M0 0"></path></svg></span>warning</div>
+<div class="admonitionContent_BuS1"><p>Back up your data <strong>first</strong>.</p><p>Then continue.</p></div></div>`,
+			want: "> **Warning:** Back up your data **first**.\n>\n> Then continue.",
+		},
+		{
+			name: "MkDocs with custom title",
+			html: `<div class="admonition tip"><p class="admonition-title">Faster builds</p><p>Enable the cache.</p></div>`,
+			want: "> **Tip: Faster builds**\n>\n> Enable the cache.",
+		},
+		{
+			name: "MkDocs collapsible",
+			html: `<details class="note"><summary>Note</summary><p>Hidden by default.</p></details>`,
+			want: "> **Note:** Hidden by default.",
+		},
+		{
+			name: "GitHub alert with code",
+			html: `<div class="markdown-alert markdown-alert-important"><p class="markdown-alert-title"><svg></svg>Important</p><pre><code class="language-sh">make clean</code></pre></div>`,
+			want: "> **Important:**\n>\n> ```sh\n> make clean\n> ```",
+		},
+		{
+			name: "VitePress",
+			html: `<div class="danger custom-block"><p class="custom-block-title">DANGER</p><p>Do not run this in production.</p></div>`,
+			want: "> **Danger:** Do not run this in production.",
+		},
+		{
+			name: "unknown kind",
+			html: `<div class="admonition"><p>Plain text.</p></div>`,
+			want: "Plain text.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, tt.html, Options{}); got != tt.want {
+				t.Errorf("Body =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
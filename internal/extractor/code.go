@@ -0,0 +1,161 @@
+package extractor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// gutterClasses mark line-number columns added by syntax highlighters
+// (Pygments, Rouge, highlight.js line numbers, Prism line-numbers plugin)
+var gutterClasses = map[string]bool{
+	"linenos": true, "lineno": true, "gutter": true, "rouge-gutter": true,
+	"line-numbers-rows": true, "hljs-ln-numbers": true, "line-number": true,
+	"linenumber": true, "ln": true, "lnt": true,
+}
+
+// plainLanguages are language names that mean "no highlighting"
+var plainLanguages = map[string]bool{
+	"": true, "none": true, "nohighlight": true, "plain": true, "plaintext": true, "text": true, "txt": true,
+}
+
+// renderCode renders a <pre> element as a fenced code block, keeping its text verbatim
+func (r *renderer) renderCode(pre *html.Node) []string {
+	text := strings.TrimRight(codeText(pre), " \t\n")
+	text = strings.TrimLeft(text, "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	f := codeFence(text)
+	return []string{f + codeLanguage(pre) + "\n" + text + "\n" + f}
+}
+
+// codeText returns the text of a code block. Highlighting markup is dropped,
+// <br> and line-level block elements become newlines and gutters are skipped.
+func codeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		switch c.Type {
+		case html.TextNode:
+			sb.WriteString(c.Data)
+			return
+		case html.ElementNode, html.DocumentNode:
+		default:
+			return
+		}
+		if c.Type == html.ElementNode {
+			if c.DataAtom == atom.Br {
+				sb.WriteString("\n")
+				return
+			}
+			if c.DataAtom == atom.Button || c.DataAtom == atom.Script || c.DataAtom == atom.Style || isGutter(c) {
+				return
+			}
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		// Highlighters that render each line as a block element rely on
+		// layout instead of newline characters
+		if c != n && (c.DataAtom == atom.Div || c.DataAtom == atom.P) && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// isGutter reports whether n holds line numbers rather than code
+func isGutter(n *html.Node) bool {
+	for _, class := range htmlutil.Classes(n) {
+		if gutterClasses[class] {
+			return true
+		}
+	}
+	return htmlutil.HasAttr(n, "data-line-number") && htmlutil.Text(n) != "" && strings.Trim(htmlutil.Text(n), "0123456789") == ""
+}
+
+// codeLanguage detects the language of a code block from class="language-*",
+// class="lang-*", data-lang or data-language on the <pre>, its <code> child or
+// wrapper elements, and from highlight.js "hljs <lang>" classes
+func codeLanguage(pre *html.Node) string {
+	candidates := []*html.Node{}
+	if code := htmlutil.Find(pre, htmlutil.ByTag(atom.Code)); code != nil {
+		candidates = append(candidates, code)
+	}
+	candidates = append(candidates, pre)
+	for p, depth := pre.Parent, 0; p != nil && p.Type == html.ElementNode && depth < 3; p, depth = p.Parent, depth+1 {
+		candidates = append(candidates, p)
+	}
+
+	for _, n := range candidates {
+		for _, attr := range []string{"data-lang", "data-language"} {
+			if lang := normalizeLanguage(htmlutil.Attr(n, attr)); lang != "" {
+				return lang
+			}
+		}
+		classes := htmlutil.Classes(n)
+		for _, class := range classes {
+			for _, prefix := range []string{"language-", "lang-"} {
+				if strings.HasPrefix(class, prefix) {
+					return normalizeLanguage(strings.TrimPrefix(class, prefix))
+				}
+			}
+		}
+		if n.DataAtom == atom.Code && hasClassName(classes, "hljs") {
+			for _, class := range classes {
+				if class != "hljs" && !strings.HasPrefix(class, "hljs-") {
+					return normalizeLanguage(class)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if plainLanguages[lang] {
+		return ""
+	}
+	return lang
+}
+
+// codeFence returns a backtick fence longer than any backtick run at the start
+// of a line of text
+func codeFence(text string) string {
+	longest := 0
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, "`"))
+		if n > longest {
+			longest = n
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// isCodeLabel reports whether n is a language label rendered next to a code
+// block, such as VitePress' <span class="lang">
+func isCodeLabel(n *html.Node) bool {
+	if n.Type != html.ElementNode || !htmlutil.HasClass(n, "lang") || n.Parent == nil {
+		return false
+	}
+	return htmlutil.Find(n.Parent, htmlutil.ByTag(atom.Pre)) != nil
+}
+
+func hasClassName(classes []string, name string) bool {
+	for _, c := range classes {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
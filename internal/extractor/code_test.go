@@ -0,0 +1,71 @@
+package extractor
+
+import "testing"
+
+func TestRenderCode(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "language class with entities and tabs",
+			html: "<pre><code class=\"language-go\">func main() {\n\tif a &lt; b &amp;&amp; c {\n\t}\n}\n</code></pre>",
+			want: "```go\nfunc main() {\n\tif a < b && c {\n\t}\n}\n```",
+		},
+		{
+			name: "data-lang",
+			html: "<pre><code data-lang=\"YAML\">key: value</code></pre>",
+			want: "```yaml\nkey: value\n```",
+		},
+		{
+			name: "prism token spans",
+			html: `<pre class="language-js"><code class="language-js"><span class="token keyword">const</span> x <span class="token operator">=</span> <span class="token number">1</span><span class="token punctuation">;</span></code></pre>`,
+			want: "```js\nconst x = 1;\n```",
+		},
+		{
+			name: "highlight.js",
+			html: `<pre><code class="hljs python"><span class="hljs-keyword">def</span> <span class="hljs-title">f</span>():
+    <span class="hljs-keyword">pass</span></code></pre>`,
+			want: "```python\ndef f():\n    pass\n```",
+		},
+		{
+			name: "docusaurus token lines with br",
+			html: `<div class="language-bash codeBlockContainer"><pre class="prism-code"><code><span class="token-line"><span class="token plain">npm install</span><br></span><span class="token-line"><span class="token plain">npm start</span><br></span></code></pre><button>Copy</button></div>`,
+			want: "```bash\nnpm install\nnpm start\n```",
+		},
+		{
+			name: "shiki with vitepress label",
+			html: "<div class=\"language-ts vp-adaptive-theme\"><button title=\"Copy\"></button><span class=\"lang\">ts</span><pre class=\"shiki\"><code><span class=\"line\"><span style=\"color:#D73A49\">let</span><span> a</span></span>\n<span class=\"line\"><span>a++</span></span></code></pre></div>",
+			want: "```ts\nlet a\na++\n```",
+		},
+		{
+			name: "pygments line numbers",
+			html: "<div class=\"language-python highlight\"><pre><span></span><code><span class=\"linenos\">1</span>import os\n<span class=\"linenos\">2</span>print(os.name)\n</code></pre></div>",
+			want: "```python\nimport os\nprint(os.name)\n```",
+		},
+		{
+			name: "lines as divs",
+			html: `<pre data-language="sh"><code><div class="ec-line">echo one</div><div class="ec-line">echo two</div></code></pre>`,
+			want: "```sh\necho one\necho two\n```",
+		},
+		{
+			name: "no language",
+			html: "<pre>plain\n  indented</pre>",
+			want: "```\nplain\n  indented\n```",
+		},
+		{
+			name: "nested fences",
+			html: "<pre><code class=\"language-markdown\">```go\nx\n```</code></pre>",
+			want: "````markdown\n```go\nx\n```\n````",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, tt.html, Options{}); got != tt.want {
+				t.Errorf("Body =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
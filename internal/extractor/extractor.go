@@ -0,0 +1,98 @@
+// Package extractor extracts titles, metadata and content from HTML pages
+package extractor
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// Page is the information extracted from a single HTML document
+type Page struct {
+	Title    string            // Page title
+	Excerpt  string            // Short description of the page, empty if the page has none
+	Metadata map[string]string // Metadata gathered from meta tags and JSON-LD, see the Meta* keys
+	Body     string            // Main content rendered as Markdown, without the title heading
+	Headings []Heading         // Headings of the body, in order
+	FAQ      []QA              // Question/answer pairs when the page is an FAQ
+	Content  *html.Node        // Root of the main content
+}
+
+// Extract parses an HTML document and extracts its title, excerpt, metadata
+// and Markdown body
+func Extract(r io.Reader, opts Options) (*Page, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	page := &Page{
+		Metadata: ExtractMetadata(doc),
+		Content:  MainContent(doc),
+	}
+
+	// The first heading of the content is the most specific title,
+	// metadata is used when the content has none
+	rd := &renderer{opts: opts, skip: make(map[*html.Node]bool)}
+	if heading := htmlutil.Find(page.Content, isHeading); heading != nil {
+		page.Title = headingText(heading)
+		// The title is written by the formatter, so it is left out of the body
+		rd.skip[heading] = true
+	}
+	page.Title = firstNonEmpty(page.Title,
+		page.Metadata[MetaOGTitle],
+		page.Metadata[MetaSchemaHeadline],
+		page.Metadata[MetaTitle])
+
+	page.Excerpt = firstNonEmpty(
+		page.Metadata[MetaDescription],
+		page.Metadata[MetaOGDescription],
+		page.Metadata[MetaSchemaDescription])
+
+	rd.faq = newFAQState(doc, page.Content, page.Metadata)
+	page.Body = rd.render(page.Content)
+	page.Headings = rd.headings
+	if rd.faq != nil {
+		page.FAQ = rd.faq.pairs
+	}
+
+	return page, nil
+}
+
+// MainContent returns the element holding the main content of a document:
+// the first <main>, [role=main] or <article>, falling back to <body>
+func MainContent(doc *html.Node) *html.Node {
+	for _, match := range []func(*html.Node) bool{
+		htmlutil.ByTag(atom.Main),
+		func(n *html.Node) bool { return htmlutil.Attr(n, "role") == "main" },
+		htmlutil.ByTag(atom.Article),
+		htmlutil.ByTag(atom.Body),
+	} {
+		if n := htmlutil.Find(doc, match); n != nil {
+			return n
+		}
+	}
+	return doc
+}
+
+// isHeading reports whether n is an h1-h6 element
+func isHeading(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return true
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,206 @@
+package extractor
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// FAQ output formats
+const (
+	FAQFormatMarkdown = "markdown" // **Q:** / A: blocks
+	FAQFormatJSON     = "json"     // A fenced JSON array of {"question", "answer"} pairs
+)
+
+// QA is a question and its answer found on an FAQ page
+type QA struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// faqState tracks the FAQ structures of a page while its content is rendered
+type faqState struct {
+	items   map[*html.Node]bool // <details> and microdata Question elements
+	pairs   []QA                // Pairs rendered from the content so far
+	emitted bool                // Whether the JSON placeholder has been written
+	schema  []QA                // Pairs from FAQPage JSON-LD
+}
+
+// newFAQState detects FAQ structures in the content of a page. It returns nil
+// when the page does not look like an FAQ.
+func newFAQState(doc, content *html.Node, meta map[string]string) *faqState {
+	state := &faqState{items: make(map[*html.Node]bool)}
+
+	// schema.org microdata: itemtype="https://schema.org/Question"
+	for _, n := range htmlutil.FindAll(content, isMicrodataQuestion) {
+		state.items[n] = true
+	}
+
+	// <details><summary> pairs count when the page declares FAQPage or when
+	// most summaries are phrased as questions
+	details := htmlutil.FindAll(content, func(n *html.Node) bool {
+		return n.DataAtom == atom.Details && htmlutil.Find(n, htmlutil.ByTag(atom.Summary)) != nil
+	})
+	questions := 0
+	for _, d := range details {
+		summary := htmlutil.Text(htmlutil.Find(d, htmlutil.ByTag(atom.Summary)))
+		if strings.HasSuffix(summary, "?") || strings.HasSuffix(summary, "？") {
+			questions++
+		}
+	}
+	isFAQPage := HasSchemaType(meta, "FAQPage")
+	if isFAQPage || len(details) >= 2 && questions*2 > len(details) {
+		for _, d := range details {
+			state.items[d] = true
+		}
+	}
+
+	if isFAQPage {
+		state.schema = schemaFAQ(doc)
+	}
+	if len(state.items) == 0 && len(state.schema) == 0 {
+		return nil
+	}
+	return state
+}
+
+// isItem reports whether n is rendered as a question/answer pair
+func (f *faqState) isItem(n *html.Node) bool {
+	return f.items[n]
+}
+
+// renderItem renders a <details> or microdata Question element
+func (f *faqState) renderItem(r *renderer, n *html.Node) []string {
+	var question *html.Node
+	var answer *html.Node
+	if n.DataAtom == atom.Details {
+		question = htmlutil.Find(n, htmlutil.ByTag(atom.Summary))
+		answer = n
+	} else {
+		question = htmlutil.Find(n, func(c *html.Node) bool { return htmlutil.Attr(c, "itemprop") == "name" })
+		answer = htmlutil.Find(n, func(c *html.Node) bool { return htmlutil.Attr(c, "itemprop") == "acceptedAnswer" })
+	}
+	if question == nil || answer == nil {
+		return r.renderBlocks(n)
+	}
+
+	qa := QA{Question: cleanInline(r.renderInlineChildren(question))}
+	r.skip[question] = true
+	qa.Answer = strings.Join(r.renderBlocks(answer), "\n\n")
+	f.pairs = append(f.pairs, qa)
+
+	if r.opts.FAQFormat != FAQFormatJSON {
+		return []string{formatQA(qa)}
+	}
+	// In JSON mode all pairs are written as one block where the first one
+	// appears; the placeholder is replaced once every item is rendered
+	if f.emitted {
+		return nil
+	}
+	f.emitted = true
+	return []string{faqPlaceholder}
+}
+
+// faqPlaceholder marks the position of the JSON block in the rendered body
+const faqPlaceholder = "\x00faq\x00"
+
+// finish replaces the JSON placeholder in a rendered body
+func (f *faqState) finish(body string) string {
+	if !f.emitted {
+		return body
+	}
+	return strings.Replace(body, faqPlaceholder, formatQAJSON(f.pairs), 1)
+}
+
+// trailingBlocks renders FAQPage JSON-LD pairs when the content itself had no
+// FAQ markup
+func (f *faqState) trailingBlocks(opts Options) []string {
+	if len(f.pairs) > 0 || len(f.schema) == 0 {
+		return nil
+	}
+	f.pairs = f.schema
+	if opts.FAQFormat == FAQFormatJSON {
+		return []string{formatQAJSON(f.schema)}
+	}
+	blocks := make([]string, len(f.schema))
+	for i, qa := range f.schema {
+		blocks[i] = formatQA(qa)
+	}
+	return blocks
+}
+
+func formatQA(qa QA) string {
+	return "**Q:** " + qa.Question + "\n\nA: " + qa.Answer
+}
+
+func formatQAJSON(pairs []QA) string {
+	data, _ := json.MarshalIndent(pairs, "", "  ")
+	return "```json\n" + string(data) + "\n```"
+}
+
+func isMicrodataQuestion(n *html.Node) bool {
+	itemType := htmlutil.Attr(n, "itemtype")
+	return strings.HasSuffix(itemType, "schema.org/Question") && htmlutil.HasAttr(n, "itemscope")
+}
+
+// schemaFAQ returns the question/answer pairs of FAQPage JSON-LD objects
+func schemaFAQ(doc *html.Node) []QA {
+	var pairs []QA
+	for _, script := range htmlutil.FindAll(doc, htmlutil.ByTag(atom.Script)) {
+		if !strings.EqualFold(strings.TrimSpace(htmlutil.Attr(script, "type")), "application/ld+json") {
+			continue
+		}
+		for _, obj := range parseJSONLD(htmlutil.RawText(script)) {
+			isFAQ := false
+			for _, t := range jsonLDTypes(obj) {
+				isFAQ = isFAQ || t == "FAQPage"
+			}
+			if !isFAQ {
+				continue
+			}
+			entities, ok := obj["mainEntity"].([]any)
+			if !ok {
+				if single, ok := obj["mainEntity"].(map[string]any); ok {
+					entities = []any{single}
+				}
+			}
+			for _, e := range entities {
+				q, ok := e.(map[string]any)
+				if !ok {
+					continue
+				}
+				answer, _ := q["acceptedAnswer"].(map[string]any)
+				qa := QA{
+					Question: htmlToText(jsonString(q["name"])),
+					Answer:   htmlToText(jsonString(answer["text"])),
+				}
+				if qa.Question != "" && qa.Answer != "" {
+					pairs = append(pairs, qa)
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// htmlToText strips markup from an HTML fragment, as used in JSON-LD answers
+func htmlToText(s string) string {
+	if !strings.Contains(s, "<") {
+		return strings.TrimSpace(s)
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, DataAtom: atom.Div, Data: "div"})
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	var parts []string
+	for _, n := range nodes {
+		if text := htmlutil.Text(n); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,83 @@
+package extractor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const faqDetailsPage = `<html><body><main>
+<h1>FAQ</h1>
+<p>Common questions.</p>
+<details><summary>How do I install it?</summary><p>Run <code>go install</code>.</p></details>
+<details><summary>Is it free?</summary><p>Yes.</p><p>Apache 2.0 licensed.</p></details>
+</main></body></html>`
+
+func TestExtractFAQDetails(t *testing.T) {
+	page, err := Extract(strings.NewReader(faqDetailsPage), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := "Common questions.\n\n" +
+		"**Q:** How do I install it?\n\nA: Run `go install`.\n\n" +
+		"**Q:** Is it free?\n\nA: Yes.\n\nApache 2.0 licensed."
+	if page.Body != want {
+		t.Errorf("Body =\n%s\nwant\n%s", page.Body, want)
+	}
+	if len(page.FAQ) != 2 || page.FAQ[1].Question != "Is it free?" {
+		t.Errorf("FAQ = %+v", page.FAQ)
+	}
+}
+
+func TestExtractFAQJSON(t *testing.T) {
+	page, err := Extract(strings.NewReader(faqDetailsPage), Options{FAQFormat: FAQFormatJSON})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	start := strings.Index(page.Body, "```json\n")
+	end := strings.LastIndex(page.Body, "\n```")
+	if !strings.HasPrefix(page.Body, "Common questions.\n\n") || start < 0 || end < start {
+		t.Fatalf("Expected a JSON block after the intro, got:\n%s", page.Body)
+	}
+	var pairs []QA
+	if err := json.Unmarshal([]byte(page.Body[start+len("```json\n"):end]), &pairs); err != nil {
+		t.Fatalf("Invalid JSON block: %v\n%s", err, page.Body)
+	}
+	if len(pairs) != 2 || pairs[0].Answer != "Run `go install`." {
+		t.Errorf("pairs = %+v", pairs)
+	}
+}
+
+func TestExtractFAQSchema(t *testing.T) {
+	page, err := Extract(strings.NewReader(`<html><head>
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "FAQPage", "mainEntity": [
+  {"@type": "Question", "name": "Where are logs stored?",
+   "acceptedAnswer": {"@type": "Answer", "text": "<p>In <code>~/.cache</code>.</p>"}}
+]}
+</script></head>
+<body><main><h1>Support</h1><p>Where are logs stored? In ~/.cache.</p></main></body></html>`), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if !strings.HasSuffix(page.Body, "**Q:** Where are logs stored?\n\nA: In ~/.cache.") {
+		t.Errorf("Expected JSON-LD pairs at the end of the body, got:\n%s", page.Body)
+	}
+}
+
+func TestExtractDetailsNotFAQ(t *testing.T) {
+	page, err := Extract(strings.NewReader(`<html><body><main>
+<h1>Config</h1>
+<details><summary>Full example</summary><p>All options.</p></details>
+</main></body></html>`), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if page.Body != "**Full example**\n\nAll options." || page.FAQ != nil {
+		t.Errorf("Body = %q, FAQ = %+v", page.Body, page.FAQ)
+	}
+}
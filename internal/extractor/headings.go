@@ -0,0 +1,78 @@
+package extractor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// Heading is a heading of the page body
+type Heading struct {
+	Level int    // 1-6
+	Text  string // Plain text of the heading
+	ID    string // Anchor of the heading in the page, empty when it has none
+}
+
+// permalinkClasses mark the "¶"/"#" links themes add next to headings
+var permalinkClasses = []string{"headerlink", "header-anchor", "hash-link", "anchor-link", "heading-anchor", "anchorjs-link"}
+
+// isPermalink reports whether n is a self-link decorating a heading
+func isPermalink(n *html.Node) bool {
+	if !htmlutil.IsElement(n, atom.A) || !strings.HasPrefix(htmlutil.Attr(n, "href"), "#") {
+		return false
+	}
+	for _, class := range permalinkClasses {
+		if htmlutil.HasClass(n, class) {
+			return true
+		}
+	}
+	switch strings.TrimSpace(htmlutil.Text(n)) {
+	case "", "#", "¶", "§", "🔗":
+		return true
+	}
+	return false
+}
+
+// headingText returns the plain text of a heading without permalinks
+func headingText(n *html.Node) string {
+	var sb strings.Builder
+	htmlutil.Walk(n, func(c *html.Node) bool {
+		if isPermalink(c) {
+			return false
+		}
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+		return true
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// headingID returns the anchor of a heading: its id, the id or name of an
+// anchor inside it, or the target of its permalink
+func headingID(n *html.Node) string {
+	if id := htmlutil.Attr(n, "id"); id != "" {
+		return id
+	}
+	var id string
+	htmlutil.Walk(n, func(c *html.Node) bool {
+		if id != "" {
+			return false
+		}
+		if htmlutil.IsElement(c, atom.A) {
+			switch {
+			case htmlutil.Attr(c, "id") != "":
+				id = htmlutil.Attr(c, "id")
+			case htmlutil.Attr(c, "name") != "":
+				id = htmlutil.Attr(c, "name")
+			case isPermalink(c):
+				id = strings.TrimPrefix(htmlutil.Attr(c, "href"), "#")
+			}
+		}
+		return true
+	})
+	return id
+}
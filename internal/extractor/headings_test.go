@@ -0,0 +1,39 @@
+package extractor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractHeadings(t *testing.T) {
+	const page = `<html><body><main>
+<h1 id="install">Install<a class="headerlink" href="#install">¶</a></h1>
+<p>Intro.</p>
+<h2 id="linux">Linux</h2>
+<p>Use apt.</p>
+<h3><a name="debian"></a>Debian &amp; <code>apt</code></h3>
+<h2><a class="header-anchor" href="#mac-os" aria-hidden="true">#</a> macOS</h2>
+<h2>Other</h2>
+</main></body></html>`
+
+	p, err := Extract(strings.NewReader(page), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if p.Title != "Install" {
+		t.Errorf("Title = %q, want %q", p.Title, "Install")
+	}
+	want := []Heading{
+		{Level: 2, Text: "Linux", ID: "linux"},
+		{Level: 3, Text: "Debian & apt", ID: "debian"},
+		{Level: 2, Text: "macOS", ID: "mac-os"},
+		{Level: 2, Text: "Other"},
+	}
+	if !reflect.DeepEqual(p.Headings, want) {
+		t.Errorf("Headings = %+v, want %+v", p.Headings, want)
+	}
+	if wantBody := "Intro.\n\n## Linux\n\nUse apt.\n\n### Debian & `apt`\n\n## macOS\n\n## Other"; p.Body != wantBody {
+		t.Errorf("Body =\n%s\nwant\n%s", p.Body, wantBody)
+	}
+}
@@ -0,0 +1,57 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// Image rendering modes
+const (
+	ImageModeMarkdown = "markdown" // ![alt](src) with the source resolved against the page URL
+	ImageModeAlt      = "alt"      // Alt text only
+	ImageModeDrop     = "drop"     // Images are left out
+)
+
+// renderImage renders an <img> according to Options.ImageMode
+func (r *renderer) renderImage(img *html.Node) string {
+	alt := collapseSpace(strings.TrimSpace(htmlutil.Attr(img, "alt")))
+
+	switch r.opts.ImageMode {
+	case ImageModeDrop:
+		return ""
+	case ImageModeAlt:
+		return alt
+	}
+
+	src := strings.TrimSpace(htmlutil.Attr(img, "src"))
+	if src == "" || strings.HasPrefix(src, "data:") {
+		// Lazy-loaded images keep the real source in data-src
+		src = strings.TrimSpace(htmlutil.Attr(img, "data-src"))
+	}
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return alt
+	}
+	alt = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(alt)
+	return "![" + alt + "](" + resolveURL(r.opts.PageURL, src) + ")"
+}
+
+// resolveURL resolves ref against the URL of the page it appears on. It
+// returns ref unchanged when either cannot be parsed.
+func resolveURL(pageURL, ref string) string {
+	if pageURL == "" {
+		return ref
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
@@ -0,0 +1,53 @@
+package extractor
+
+import "testing"
+
+func TestRenderImages(t *testing.T) {
+	const figure = `<figure><img src="../img/flow.png" alt="Request flow"><figcaption>Figure 1: the <em>request</em> flow</figcaption></figure>`
+
+	tests := []struct {
+		name string
+		html string
+		opts Options
+		want string
+	}{
+		{
+			name: "markdown with figure caption",
+			html: figure,
+			opts: Options{ImageMode: ImageModeMarkdown, PageURL: "/guide/intro"},
+			want: "![Request flow](/img/flow.png)\n\nFigure 1: the *request* flow",
+		},
+		{
+			name: "absolute page URL",
+			html: `<p>See <img src="diagram.svg" alt="Diagram [v2]"> below</p>`,
+			opts: Options{ImageMode: ImageModeMarkdown, PageURL: "https://docs.example.com/guide/intro"},
+			want: `See ![Diagram \[v2\]](https://docs.example.com/guide/diagram.svg) below`,
+		},
+		{
+			name: "lazy-loaded source",
+			html: `<img src="data:image/gif;base64,R0lGOD" data-src="/img/lazy.png" alt="Lazy">`,
+			opts: Options{ImageMode: ImageModeMarkdown},
+			want: "![Lazy](/img/lazy.png)",
+		},
+		{
+			name: "alt text only",
+			html: figure,
+			opts: Options{ImageMode: ImageModeAlt},
+			want: "Request flow\n\nFigure 1: the *request* flow",
+		},
+		{
+			name: "dropped",
+			html: figure,
+			opts: Options{ImageMode: ImageModeDrop},
+			want: "Figure 1: the *request* flow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, tt.html, tt.opts); got != tt.want {
+				t.Errorf("Body =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package extractor
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Handling of links to pages that are not part of the output
+const (
+	ExcludedLinksKeep = "keep" // Links are kept as they are
+	ExcludedLinksDrop = "drop" // Only the link text is kept
+	ExcludedLinksMark = "mark" // The link is kept and marked as excluded
+)
+
+// excludedMark is appended to links to excluded pages in ExcludedLinksMark mode
+const excludedMark = " (not included)"
+
+// renderLink renders an <a> with the given text, rewriting its target
+func (r *renderer) renderLink(text, href string) string {
+	target, excluded := r.rewriteLink(href)
+	if excluded {
+		switch r.opts.ExcludedLinks {
+		case ExcludedLinksDrop:
+			return text
+		case ExcludedLinksMark:
+			return "[" + text + "](" + target + ")" + excludedMark
+		}
+	}
+	return "[" + text + "](" + target + ")"
+}
+
+// rewriteLink resolves href against the page URL and, for links to pages of
+// the site, points it to the Markdown mirror when Options.MarkdownLinks is set.
+// excluded reports whether href targets a site page missing from the output.
+func (r *renderer) rewriteLink(href string) (target string, excluded bool) {
+	target = resolveURL(r.opts.PageURL, href)
+	u, err := url.Parse(target)
+	if err != nil || !r.isLocal(u) || !isPagePath(u.Path) {
+		return target, false
+	}
+	if r.opts.IsPage != nil && !r.opts.IsPage(u.Path) {
+		return target, true
+	}
+	if r.opts.MarkdownLinks {
+		u.Path = markdownPath(u.Path)
+		target = u.String()
+	}
+	return target, false
+}
+
+// isLocal reports whether u points to the same site as the page
+func (r *renderer) isLocal(u *url.URL) bool {
+	if u.Scheme == "" && u.Host == "" {
+		return u.Path != ""
+	}
+	page, err := url.Parse(r.opts.PageURL)
+	if err != nil || page.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, page.Host) && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// isPagePath reports whether p looks like a page rather than an asset such as an image or archive
+func isPagePath(p string) bool {
+	if p == "" {
+		return false
+	}
+	switch path.Ext(p) {
+	case "", ".html", ".htm":
+		return true
+	}
+	return false
+}
+
+// markdownPath returns the path of the Markdown mirror of the page at p,
+// e.g. "/guide/setup.html" → "/guide/setup.md" and "/guide/" → "/guide/index.md"
+func markdownPath(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return p + "index.md"
+	}
+	p = strings.TrimSuffix(p, path.Ext(p))
+	return p + ".md"
+}
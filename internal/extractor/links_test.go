@@ -0,0 +1,48 @@
+package extractor
+
+import "testing"
+
+func TestRewriteLinks(t *testing.T) {
+	const body = `<p>See <a href="../guide/setup.html#linux">setup</a>, <a href="/blog/">the blog</a>, ` +
+		`<a href="#usage">usage</a>, <a href="files/app.zip">the archive</a> and <a href="https://go.dev/doc/">Go</a>.</p>`
+	isPage := func(urlPath string) bool { return urlPath != "/blog/" }
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "resolved against the page URL",
+			opts: Options{PageURL: "/api/intro"},
+			want: "See [setup](/guide/setup.html#linux), [the blog](/blog/), [usage](/api/intro#usage), " +
+				"[the archive](/api/files/app.zip) and [Go](https://go.dev/doc/).",
+		},
+		{
+			name: "absolute page URL and Markdown mirrors",
+			opts: Options{PageURL: "https://docs.example.com/api/intro", MarkdownLinks: true},
+			want: "See [setup](https://docs.example.com/guide/setup.md#linux), [the blog](https://docs.example.com/blog/index.md), " +
+				"[usage](https://docs.example.com/api/intro.md#usage), [the archive](https://docs.example.com/api/files/app.zip) and [Go](https://go.dev/doc/).",
+		},
+		{
+			name: "excluded pages dropped",
+			opts: Options{PageURL: "/api/intro", IsPage: isPage, ExcludedLinks: ExcludedLinksDrop},
+			want: "See [setup](/guide/setup.html#linux), the blog, [usage](/api/intro#usage), " +
+				"[the archive](/api/files/app.zip) and [Go](https://go.dev/doc/).",
+		},
+		{
+			name: "excluded pages marked",
+			opts: Options{PageURL: "/api/intro", IsPage: isPage, ExcludedLinks: ExcludedLinksMark},
+			want: "See [setup](/guide/setup.html#linux), [the blog](/blog/) (not included), [usage](/api/intro#usage), " +
+				"[the archive](/api/files/app.zip) and [Go](https://go.dev/doc/).",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, body, tt.opts); got != tt.want {
+				t.Errorf("Body =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package extractor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// Options controls how page content is rendered to Markdown
+type Options struct {
+	// FAQFormat selects how question/answer pairs are rendered:
+	// FAQFormatMarkdown (default) or FAQFormatJSON
+	FAQFormat string
+	// PreferredTabs lists tab labels or code languages in order of preference.
+	// Tabbed groups containing one of them are reduced to that tab; other
+	// groups emit every tab as a labeled block.
+	PreferredTabs []string
+	// MaxTableColumns converts tables with more columns into per-row
+	// key/value lists (0 = always render pipe tables)
+	MaxTableColumns int
+	// ImageMode selects how images are rendered: ImageModeMarkdown (default),
+	// ImageModeAlt or ImageModeDrop
+	ImageMode string
+	// PageURL is the URL of the page, absolute or root-relative, used to
+	// resolve relative image sources and links
+	PageURL string
+	// MarkdownLinks points links to pages of the site to their Markdown
+	// mirrors, e.g. "/guide/setup.md" instead of "/guide/setup.html"
+	MarkdownLinks bool
+	// IsPage reports whether a site-local URL path is a page of the output.
+	// Links to other pages are handled according to ExcludedLinks. When nil,
+	// every page is assumed to be included.
+	IsPage func(urlPath string) bool
+	// ExcludedLinks selects how links to excluded pages are rendered:
+	// ExcludedLinksKeep (default), ExcludedLinksDrop or ExcludedLinksMark
+	ExcludedLinks string
+}
+
+// renderer converts the main content of a page to Markdown
+type renderer struct {
+	opts Options
+	skip map[*html.Node]bool // Nodes left out of the output, such as the title heading
+	faq  *faqState
+
+	headings []Heading // Headings rendered so far
+}
+
+// skippedTags are elements that never contribute to the body
+var skippedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Button: true, atom.Form: true, atom.Input: true,
+	atom.Select: true, atom.Textarea: true, atom.Svg: true, atom.Iframe: true,
+	atom.Hr: true,
+}
+
+// blockTags are elements rendered as separate blocks
+var blockTags = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Dd: true, atom.Details: true, atom.Dialog: true, atom.Div: true, atom.Dl: true,
+	atom.Dt: true, atom.Fieldset: true, atom.Figcaption: true, atom.Figure: true,
+	atom.Footer: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+	atom.H5: true, atom.H6: true, atom.Header: true, atom.Li: true, atom.Main: true,
+	atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true, atom.Summary: true,
+	atom.Table: true, atom.Tbody: true, atom.Td: true, atom.Tfoot: true, atom.Th: true,
+	atom.Thead: true, atom.Tr: true, atom.Ul: true, atom.Caption: true,
+}
+
+// render returns the Markdown body of n
+func (r *renderer) render(n *html.Node) string {
+	blocks := r.renderBlocks(n)
+	if r.faq == nil {
+		return strings.Join(blocks, "\n\n")
+	}
+	blocks = append(blocks, r.faq.trailingBlocks(r.opts)...)
+	return r.faq.finish(strings.Join(blocks, "\n\n"))
+}
+
+// renderBlocks renders the children of n as a list of Markdown blocks, joining
+// runs of inline children into paragraphs
+func (r *renderer) renderBlocks(n *html.Node) []string {
+	var blocks []string
+	var para strings.Builder
+	flush := func() {
+		if text := cleanInline(para.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		para.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if r.skipped(c) {
+			continue
+		}
+		if isBlock(c) {
+			flush()
+			blocks = append(blocks, r.renderBlock(c)...)
+		} else {
+			para.WriteString(r.renderInline(c))
+		}
+	}
+	flush()
+	return blocks
+}
+
+// renderBlock renders a block element
+func (r *renderer) renderBlock(n *html.Node) []string {
+	if r.faq != nil && r.faq.isItem(n) {
+		return r.faq.renderItem(r, n)
+	}
+	if tabs := detectTabs(n); tabs != nil {
+		return r.renderTabs(tabs)
+	}
+	if a := detectAdmonition(n); a != nil {
+		return r.renderAdmonition(n, a)
+	}
+
+	switch n.DataAtom {
+	case atom.P:
+		if text := cleanInline(r.renderInlineChildren(n)); text != "" {
+			return []string{text}
+		}
+		return nil
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := cleanInline(r.renderInlineChildren(n))
+		if text == "" {
+			return nil
+		}
+		level := int(n.Data[1] - '0')
+		r.headings = append(r.headings, Heading{Level: level, Text: headingText(n), ID: headingID(n)})
+		return []string{strings.Repeat("#", level) + " " + strings.ReplaceAll(text, "\n", " ")}
+	case atom.Ul, atom.Ol:
+		if list := r.renderList(n); list != "" {
+			return []string{list}
+		}
+		return nil
+	case atom.Pre:
+		return r.renderCode(n)
+	case atom.Blockquote:
+		inner := strings.Join(r.renderBlocks(n), "\n\n")
+		if inner == "" {
+			return nil
+		}
+		return []string{prefixLines(inner, "> ")}
+	case atom.Details:
+		return r.renderDetails(n)
+	case atom.Table:
+		return r.renderTable(n)
+	}
+	return r.renderBlocks(n)
+}
+
+// renderDetails renders a <details> element that is not an FAQ entry as its
+// bold summary followed by its content
+func (r *renderer) renderDetails(n *html.Node) []string {
+	var blocks []string
+	if summary := htmlutil.Find(n, htmlutil.ByTag(atom.Summary)); summary != nil {
+		if text := cleanInline(r.renderInlineChildren(summary)); text != "" {
+			blocks = append(blocks, "**"+text+"**")
+		}
+		r.skip[summary] = true
+	}
+	return append(blocks, r.renderBlocks(n)...)
+}
+
+// renderList renders a <ul> or <ol>, indenting nested content under each item
+func (r *renderer) renderList(list *html.Node) string {
+	ordered := list.DataAtom == atom.Ol
+	number := 1
+	if start, err := strconv.Atoi(htmlutil.Attr(list, "start")); err == nil {
+		number = start
+	}
+
+	var items []string
+	for li := list.FirstChild; li != nil; li = li.NextSibling {
+		if !htmlutil.IsElement(li, atom.Li) || r.skipped(li) {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		blocks := r.renderBlocks(li)
+		if len(blocks) == 0 {
+			continue
+		}
+		item := marker + indentLines(strings.Join(blocks, "\n"), strings.Repeat(" ", len(marker)))
+		items = append(items, item)
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderInlineChildren renders the children of n as inline Markdown
+func (r *renderer) renderInlineChildren(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if !r.skipped(c) {
+			sb.WriteString(r.renderInline(c))
+		}
+	}
+	return sb.String()
+}
+
+// renderInline renders a node inside a paragraph
+func (r *renderer) renderInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return collapseSpace(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Img:
+		return r.renderImage(n)
+	case atom.Code, atom.Kbd, atom.Samp:
+		text := strings.TrimSpace(collapseSpace(htmlutil.RawText(n)))
+		if text == "" {
+			return ""
+		}
+		return codeSpan(text)
+	case atom.Strong, atom.B:
+		return wrapInline(r.renderInlineChildren(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(r.renderInlineChildren(n), "*")
+	case atom.A:
+		text := r.renderInlineChildren(n)
+		href := strings.TrimSpace(htmlutil.Attr(n, "href"))
+		if strings.TrimSpace(text) == "" || href == "" || strings.HasPrefix(href, "javascript:") {
+			return text
+		}
+		return r.renderLink(strings.TrimSpace(text), href)
+	}
+	return r.renderInlineChildren(n)
+}
+
+func (r *renderer) skipped(n *html.Node) bool {
+	if r.skip[n] {
+		return true
+	}
+	if n.Type == html.CommentNode {
+		return true
+	}
+	return n.Type == html.ElementNode && (skippedTags[n.DataAtom] || htmlutil.HasAttr(n, "hidden") || isCodeLabel(n) || isPermalink(n))
+}
+
+// isBlock reports whether n is rendered as a block. Unknown elements, such as
+// custom elements of documentation themes, are blocks when they contain blocks.
+func isBlock(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if blockTags[n.DataAtom] {
+		return true
+	}
+	if n.DataAtom != 0 {
+		return false
+	}
+	return htmlutil.Find(n, func(c *html.Node) bool { return c != n && blockTags[c.DataAtom] }) != nil
+}
+
+var spaceRun = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// collapseSpace replaces runs of whitespace with a single space
+func collapseSpace(s string) string {
+	return spaceRun.ReplaceAllString(s, " ")
+}
+
+// cleanInline trims a rendered paragraph, keeping line breaks from <br>
+func cleanInline(s string) string {
+	lines := strings.Split(s, "\n")
+	var kept []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// wrapInline surrounds text with a Markdown marker, keeping outer spaces outside
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// codeSpan wraps text in enough backticks to contain backticks inside it
+func codeSpan(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+// prefixLines adds prefix to every line of s
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(prefix+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines indents every line of s except the first
+func indentLines(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
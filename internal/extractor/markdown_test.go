@@ -0,0 +1,68 @@
+package extractor
+
+import (
+	"strings"
+	"testing"
+)
+
+// renderBody extracts a page and returns its Markdown body
+func renderBody(t *testing.T, body string, opts Options) string {
+	t.Helper()
+	page, err := Extract(strings.NewReader("<html><body><main>"+body+"</main></body></html>"), opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	return page.Body
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "paragraphs and inline markup",
+			html: `<p>Use <strong>bold</strong>, <em>emphasis</em> and <code>code</code>.</p><p>Line one<br>line two</p>`,
+			want: "Use **bold**, *emphasis* and `code`.\n\nLine one\nline two",
+		},
+		{
+			name: "links",
+			html: `<p>See <a href="/guide/setup">the setup guide</a> or <a href="javascript:void(0)">this</a>.</p>`,
+			want: "See [the setup guide](/guide/setup) or this.",
+		},
+		{
+			name: "headings after the title",
+			html: `<h1>Title</h1><h2>Usage</h2><p>Text</p>`,
+			want: "## Usage\n\nText",
+		},
+		{
+			name: "nested lists",
+			html: `<ul><li>One<ul><li>One A</li></ul></li><li>Two</li></ul><ol start="3"><li>Three</li><li>Four</li></ol>`,
+			want: "- One\n  - One A\n- Two\n\n3. Three\n4. Four",
+		},
+		{
+			name: "blockquote",
+			html: `<blockquote><p>Quoted</p><p>Twice</p></blockquote>`,
+			want: "> Quoted\n>\n> Twice",
+		},
+		{
+			name: "skipped elements",
+			html: `<p>Kept</p><nav>Menu</nav><script>var x;</script><div hidden>Hidden</div><button>Copy</button>`,
+			want: "Kept",
+		},
+		{
+			name: "loose text in containers",
+			html: `<div>Loose <span>text</span><p>Paragraph</p>tail</div>`,
+			want: "Loose text\n\nParagraph\n\ntail",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, tt.html, Options{}); got != tt.want {
+				t.Errorf("Body =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
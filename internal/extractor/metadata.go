@@ -0,0 +1,236 @@
+package extractor
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// Keys of the metadata map
+const (
+	MetaTitle             = "title"                  // <title>
+	MetaDescription       = "description"            // <meta name="description">
+	MetaOGTitle           = "og:title"               // OpenGraph title
+	MetaOGDescription     = "og:description"         // OpenGraph description
+	MetaOGSiteName        = "og:site_name"           // OpenGraph site name
+	MetaModifiedTime      = "article:modified_time"  // Last modification, also from JSON-LD dateModified
+	MetaPublishedTime     = "article:published_time" // Publication, also from JSON-LD datePublished
+	MetaLang              = "lang"                   // <html lang>
+	MetaAuthor            = "author"                 // <meta name="author"> or JSON-LD author
+	MetaKeywords          = "keywords"               // <meta name="keywords">
+	MetaCanonical         = "canonical"              // <link rel="canonical">
+	MetaHreflang          = "hreflang"               // Comma-separated hreflang codes of <link rel="alternate">
+	MetaSchemaType        = "schema:type"            // Comma-separated JSON-LD @type values
+	MetaSchemaHeadline    = "schema:headline"        // JSON-LD headline or name
+	MetaSchemaDescription = "schema:description"     // JSON-LD description
+	MetaBreadcrumb        = "breadcrumb"             // JSON-LD BreadcrumbList names joined by " › "
+)
+
+// ExtractMetadata gathers meta tags, OpenGraph properties and schema.org JSON-LD
+// from a document into a flat map. Missing values are left out.
+func ExtractMetadata(doc *html.Node) map[string]string {
+	meta := make(map[string]string)
+	set := func(key, value string) {
+		value = strings.Join(strings.Fields(value), " ")
+		if value != "" && meta[key] == "" {
+			meta[key] = value
+		}
+	}
+
+	var types, hreflangs []string
+	htmlutil.Walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.Html:
+			set(MetaLang, htmlutil.Attr(n, "lang"))
+		case atom.Title:
+			set(MetaTitle, htmlutil.Text(n))
+		case atom.Meta:
+			content := htmlutil.Attr(n, "content")
+			key := strings.ToLower(htmlutil.Attr(n, "property"))
+			if key == "" {
+				key = strings.ToLower(htmlutil.Attr(n, "name"))
+			}
+			switch key {
+			case "description", "author", "keywords",
+				"og:title", "og:description", "og:site_name",
+				"article:modified_time", "article:published_time":
+				set(key, content)
+			case "article:author":
+				set(MetaAuthor, content)
+			}
+		case atom.Link:
+			switch strings.ToLower(htmlutil.Attr(n, "rel")) {
+			case "canonical":
+				set(MetaCanonical, htmlutil.Attr(n, "href"))
+			case "alternate":
+				if lang := strings.TrimSpace(htmlutil.Attr(n, "hreflang")); lang != "" {
+					hreflangs = append(hreflangs, lang)
+				}
+			}
+		case atom.Script:
+			if strings.EqualFold(strings.TrimSpace(htmlutil.Attr(n, "type")), "application/ld+json") {
+				for _, obj := range parseJSONLD(htmlutil.RawText(n)) {
+					types = append(types, jsonLDTypes(obj)...)
+					applyJSONLD(obj, set)
+				}
+			}
+			return false
+		}
+		return true
+	})
+
+	if len(types) > 0 {
+		meta[MetaSchemaType] = strings.Join(dedupe(types), ",")
+	}
+	if len(hreflangs) > 0 {
+		meta[MetaHreflang] = strings.Join(dedupe(hreflangs), ",")
+	}
+	return meta
+}
+
+// HasSchemaType reports whether the JSON-LD of a page declares the given @type
+func HasSchemaType(meta map[string]string, schemaType string) bool {
+	for _, t := range strings.Split(meta[MetaSchemaType], ",") {
+		if t == schemaType {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSONLD decodes a JSON-LD script into its top-level objects, unwrapping
+// arrays and @graph containers
+func parseJSONLD(text string) []map[string]any {
+	var data any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		return nil
+	}
+
+	var objects []map[string]any
+	var collect func(any)
+	collect = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]any:
+			if graph, ok := v["@graph"]; ok {
+				collect(graph)
+				return
+			}
+			objects = append(objects, v)
+		}
+	}
+	collect(data)
+	return objects
+}
+
+// jsonLDTypes returns the @type values of an object
+func jsonLDTypes(obj map[string]any) []string {
+	switch t := obj["@type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// applyJSONLD copies the fields of a JSON-LD object relevant to documentation pages
+func applyJSONLD(obj map[string]any, set func(key, value string)) {
+	for _, t := range jsonLDTypes(obj) {
+		if t == "BreadcrumbList" {
+			set(MetaBreadcrumb, breadcrumb(obj))
+			return
+		}
+	}
+
+	set(MetaSchemaHeadline, jsonString(obj["headline"]))
+	set(MetaSchemaHeadline, jsonString(obj["name"]))
+	set(MetaSchemaDescription, jsonString(obj["description"]))
+	set(MetaModifiedTime, jsonString(obj["dateModified"]))
+	set(MetaPublishedTime, jsonString(obj["datePublished"]))
+	set(MetaAuthor, jsonString(obj["author"]))
+	set(MetaKeywords, jsonString(obj["keywords"]))
+}
+
+// breadcrumb joins the names of a BreadcrumbList in position order
+func breadcrumb(obj map[string]any) string {
+	elements, _ := obj["itemListElement"].([]any)
+	type crumb struct {
+		position float64
+		name     string
+	}
+	var crumbs []crumb
+	for i, e := range elements {
+		item, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		name := jsonString(item["name"])
+		if name == "" {
+			name = jsonString(item["item"])
+		}
+		position, ok := item["position"].(float64)
+		if !ok {
+			position = float64(i + 1)
+		}
+		if name != "" {
+			crumbs = append(crumbs, crumb{position, name})
+		}
+	}
+	sort.SliceStable(crumbs, func(i, j int) bool { return crumbs[i].position < crumbs[j].position })
+
+	names := make([]string, len(crumbs))
+	for i, c := range crumbs {
+		names[i] = c.name
+	}
+	return strings.Join(names, " › ")
+}
+
+// jsonString converts a JSON-LD value to text. Objects yield their name,
+// arrays are joined with commas.
+func jsonString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		return jsonString(v["name"])
+	case []any:
+		var parts []string
+		for _, item := range v {
+			if s := jsonString(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
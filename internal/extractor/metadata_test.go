@@ -0,0 +1,92 @@
+package extractor
+
+import (
+	"strings"
+	"testing"
+)
+
+const metadataPage = `<!DOCTYPE html>
+<html lang="ja">
+<head>
+  <title>Install | Example Docs</title>
+  <meta name="description" content="How to install the CLI.">
+  <meta name="keywords" content="install, cli">
+  <meta property="og:title" content="Installing the CLI">
+  <meta property="og:description" content="Install guide">
+  <meta property="og:site_name" content="Example Docs">
+  <meta property="article:modified_time" content="2025-01-02T03:04:05Z">
+  <link rel="canonical" href="https://docs.example.com/install">
+  <link rel="alternate" hreflang="en" href="https://docs.example.com/en/install">
+  <link rel="alternate" hreflang="ja" href="https://docs.example.com/ja/install">
+  <script type="application/ld+json">
+  {"@context": "https://schema.org", "@graph": [
+    {"@type": "TechArticle", "headline": "Install the CLI", "description": "Step by step",
+     "author": {"@type": "Person", "name": "Jane Doe"}, "dateModified": "2024-12-31"},
+    {"@type": "BreadcrumbList", "itemListElement": [
+      {"@type": "ListItem", "position": 2, "name": "Guides"},
+      {"@type": "ListItem", "position": 1, "name": "Docs"},
+      {"@type": "ListItem", "position": 3, "name": "Install"}
+    ]}
+  ]}
+  </script>
+</head>
+<body><main><p>No heading here.</p></main></body>
+</html>`
+
+func TestExtractMetadata(t *testing.T) {
+	page, err := Extract(strings.NewReader(metadataPage), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := map[string]string{
+		MetaTitle:             "Install | Example Docs",
+		MetaDescription:       "How to install the CLI.",
+		MetaKeywords:          "install, cli",
+		MetaOGTitle:           "Installing the CLI",
+		MetaOGDescription:     "Install guide",
+		MetaOGSiteName:        "Example Docs",
+		MetaModifiedTime:      "2025-01-02T03:04:05Z",
+		MetaLang:              "ja",
+		MetaAuthor:            "Jane Doe",
+		MetaCanonical:         "https://docs.example.com/install",
+		MetaHreflang:          "en,ja",
+		MetaSchemaType:        "TechArticle,BreadcrumbList",
+		MetaSchemaHeadline:    "Install the CLI",
+		MetaSchemaDescription: "Step by step",
+		MetaBreadcrumb:        "Docs › Guides › Install",
+	}
+	for key, value := range want {
+		if got := page.Metadata[key]; got != value {
+			t.Errorf("Metadata[%q] = %q, want %q", key, got, value)
+		}
+	}
+
+	if !HasSchemaType(page.Metadata, "TechArticle") || HasSchemaType(page.Metadata, "FAQPage") {
+		t.Errorf("HasSchemaType() mismatch for %q", page.Metadata[MetaSchemaType])
+	}
+
+	// Without a heading in the content the title falls back to metadata
+	if page.Title != "Installing the CLI" {
+		t.Errorf("Title = %q, want og:title fallback", page.Title)
+	}
+	if page.Excerpt != "How to install the CLI." {
+		t.Errorf("Excerpt = %q, want meta description", page.Excerpt)
+	}
+}
+
+func TestExtractTitleFromContent(t *testing.T) {
+	page, err := Extract(strings.NewReader(`<html><head><title>Site</title></head><body>
+		<header><h1>Site Header</h1></header>
+		<article><h2>Article Heading</h2><p>Text</p></article>
+	</body></html>`), Options{})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if page.Title != "Article Heading" {
+		t.Errorf("Title = %q, want the first heading of the main content", page.Title)
+	}
+	if page.Excerpt != "" {
+		t.Errorf("Excerpt = %q, want empty without a description", page.Excerpt)
+	}
+}
@@ -0,0 +1,218 @@
+package extractor
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// tableCell is a cell of the grid built from a table, after spans are expanded
+type tableCell struct {
+	text   string
+	header bool
+	align  string
+}
+
+// renderTable renders a <table> as a GFM pipe table. Cells spanning several
+// columns or rows are repeated in each position they cover. Tables wider than
+// Options.MaxTableColumns become one list item per row with key/value pairs.
+// Layout tables holding code blocks or other tables are rendered as blocks.
+func (r *renderer) renderTable(table *html.Node) []string {
+	if htmlutil.Find(table, func(n *html.Node) bool {
+		return n != table && (n.DataAtom == atom.Pre || n.DataAtom == atom.Table)
+	}) != nil {
+		return r.renderBlocks(table)
+	}
+
+	var blocks []string
+	if caption := htmlutil.Find(table, htmlutil.ByTag(atom.Caption)); caption != nil {
+		if text := cleanInline(r.renderInlineChildren(caption)); text != "" {
+			blocks = append(blocks, text)
+		}
+	}
+
+	grid := r.tableGrid(table)
+	if len(grid) == 0 {
+		return blocks
+	}
+
+	// The first row is the header; GFM tables always have one
+	header, body := grid[0], grid[1:]
+	if r.opts.MaxTableColumns > 0 && len(header) > r.opts.MaxTableColumns {
+		return append(blocks, keyValueRows(header, body)...)
+	}
+	return append(blocks, pipeTable(header, body))
+}
+
+// tableGrid expands the rows of a table into a rectangular grid of cells
+func (r *renderer) tableGrid(table *html.Node) [][]tableCell {
+	var rows []*html.Node
+	htmlutil.Walk(table, func(n *html.Node) bool {
+		if n != table && n.DataAtom == atom.Table {
+			return false
+		}
+		if n.DataAtom == atom.Tr && !r.skipped(n) {
+			rows = append(rows, n)
+			return false
+		}
+		return true
+	})
+
+	var grid [][]tableCell
+	filled := make(map[[2]int]bool)
+	width := 0
+	set := func(row, col int, cell tableCell) {
+		for len(grid) <= row {
+			grid = append(grid, nil)
+		}
+		for len(grid[row]) <= col {
+			grid[row] = append(grid[row], tableCell{})
+		}
+		grid[row][col] = cell
+		filled[[2]int{row, col}] = true
+		if col+1 > width {
+			width = col + 1
+		}
+	}
+
+	for i, tr := range rows {
+		col := 0
+		for td := tr.FirstChild; td != nil; td = td.NextSibling {
+			if !htmlutil.IsElement(td, atom.Td) && !htmlutil.IsElement(td, atom.Th) {
+				continue
+			}
+			for filled[[2]int{i, col}] {
+				col++
+			}
+			cell := tableCell{
+				text:   r.cellText(td),
+				header: td.DataAtom == atom.Th || htmlutil.IsElement(tr.Parent, atom.Thead),
+				align:  cellAlign(td),
+			}
+			colspan := spanAttr(td, "colspan")
+			rowspan := spanAttr(td, "rowspan")
+			for dr := 0; dr < rowspan && i+dr < len(rows); dr++ {
+				for dc := 0; dc < colspan; dc++ {
+					set(i+dr, col+dc, cell)
+				}
+			}
+			col += colspan
+		}
+	}
+
+	// Pad rows to the full width
+	for i := range grid {
+		for len(grid[i]) < width {
+			grid[i] = append(grid[i], tableCell{})
+		}
+	}
+	return grid
+}
+
+// cellText renders the content of a cell on a single line
+func (r *renderer) cellText(td *html.Node) string {
+	text := strings.Join(r.renderBlocks(td), "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "<br>")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// pipeTable formats a header and body rows as a GFM table
+func pipeTable(header []tableCell, body [][]tableCell) string {
+	var sb strings.Builder
+	writeRow := func(cells []tableCell) {
+		sb.WriteString("|")
+		for _, c := range cells {
+			sb.WriteString(" " + c.text + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(header)
+	sb.WriteString("|")
+	for i := range header {
+		align := header[i].align
+		if align == "" && len(body) > 0 {
+			align = body[0][i].align
+		}
+		switch align {
+		case "left":
+			sb.WriteString(" :--- |")
+		case "center":
+			sb.WriteString(" :---: |")
+		case "right":
+			sb.WriteString(" ---: |")
+		default:
+			sb.WriteString(" --- |")
+		}
+	}
+	sb.WriteString("\n")
+	for _, row := range body {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// keyValueRows formats each body row as a list item of "header: value" pairs
+func keyValueRows(header []tableCell, body [][]tableCell) []string {
+	var items []string
+	for _, row := range body {
+		var lines []string
+		for i, c := range row {
+			if c.text == "" {
+				continue
+			}
+			key := header[i].text
+			if key == "" {
+				key = "Column " + strconv.Itoa(i+1)
+			}
+			line := "**" + key + ":** " + c.text
+			if len(lines) == 0 {
+				lines = append(lines, "- "+line)
+			} else {
+				lines = append(lines, "  - "+line)
+			}
+		}
+		if len(lines) > 0 {
+			items = append(items, strings.Join(lines, "\n"))
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return []string{strings.Join(items, "\n")}
+}
+
+// cellAlign returns the alignment of a cell from its align attribute or style
+func cellAlign(td *html.Node) string {
+	if align := strings.ToLower(htmlutil.Attr(td, "align")); align != "" {
+		return align
+	}
+	style := strings.ReplaceAll(strings.ToLower(htmlutil.Attr(td, "style")), " ", "")
+	for _, align := range []string{"left", "center", "right"} {
+		if strings.Contains(style, "text-align:"+align) {
+			return align
+		}
+	}
+	return ""
+}
+
+// spanAttr returns a colspan or rowspan value, defaulting to 1
+func spanAttr(td *html.Node, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(htmlutil.Attr(td, name)))
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > 100 {
+		// Guard against absurd spans such as rowspan="1000"
+		return 100
+	}
+	return n
+}
@@ -0,0 +1,61 @@
+package extractor
+
+import "testing"
+
+func TestRenderTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		html       string
+		maxColumns int
+		want       string
+	}{
+		{
+			name: "header, escaping and inline markup",
+			html: `<table><caption>Parameters</caption>
+<thead><tr><th>Name</th><th align="center">Type</th><th>Description</th></tr></thead>
+<tbody>
+<tr><td><code>limit</code></td><td>int</td><td>Max items | default <strong>10</strong></td></tr>
+<tr><td><code>cursor</code></td><td>string</td><td><p>First line</p><p>Second line</p></td></tr>
+</tbody></table>`,
+			want: "Parameters\n\n" +
+				"| Name | Type | Description |\n" +
+				"| --- | :---: | --- |\n" +
+				"| `limit` | int | Max items \\| default **10** |\n" +
+				"| `cursor` | string | First line<br>Second line |",
+		},
+		{
+			name: "colspan and rowspan",
+			html: `<table>
+<tr><th>Code</th><th colspan="2">Meaning</th></tr>
+<tr><td rowspan="2">4xx</td><td>400</td><td>Bad Request</td></tr>
+<tr><td>404</td><td>Not Found</td></tr>
+</table>`,
+			want: "| Code | Meaning | Meaning |\n" +
+				"| --- | --- | --- |\n" +
+				"| 4xx | 400 | Bad Request |\n" +
+				"| 4xx | 404 | Not Found |",
+		},
+		{
+			name:       "wide table as key/value list",
+			maxColumns: 2,
+			html: `<table><tr><th>Name</th><th>Type</th><th>Required</th></tr>
+<tr><td>id</td><td>string</td><td>yes</td></tr>
+<tr><td>tags</td><td>array</td><td></td></tr></table>`,
+			want: "- **Name:** id\n  - **Type:** string\n  - **Required:** yes\n" +
+				"- **Name:** tags\n  - **Type:** array",
+		},
+		{
+			name: "layout table with code",
+			html: "<table class=\"rouge-table\"><tr><td class=\"rouge-gutter gl\"><pre class=\"lineno\">1\n2</pre></td><td class=\"rouge-code\"><pre>a = 1\nb = 2</pre></td></tr></table>",
+			want: "```\na = 1\nb = 2\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, tt.html, Options{MaxTableColumns: tt.maxColumns}); got != tt.want {
+				t.Errorf("Body =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
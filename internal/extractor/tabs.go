@@ -0,0 +1,166 @@
+package extractor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// tabSet is a group of tabs found in the content, such as the same example
+// shown in several languages
+type tabSet struct {
+	labels []string
+	panels []*html.Node
+}
+
+// detectTabs recognizes tab markup of common documentation themes: MkDocs
+// Material (.tabbed-set), VitePress code groups (.vp-code-group) and ARIA tabs
+// (role="tablist"/"tabpanel", used by Docusaurus). It returns nil for other elements.
+func detectTabs(n *html.Node) *tabSet {
+	if n.Type != html.ElementNode {
+		return nil
+	}
+
+	switch {
+	case htmlutil.HasClass(n, "tabbed-set"):
+		set := &tabSet{labels: labelTexts(n)}
+		set.panels = findOwn(n, func(c *html.Node) bool { return htmlutil.HasClass(c, "tabbed-block") })
+		if len(set.panels) == 0 {
+			set.panels = findOwn(n, func(c *html.Node) bool { return htmlutil.HasClass(c, "tabbed-content") })
+		}
+		return set.valid()
+	case htmlutil.HasClass(n, "vp-code-group"):
+		set := &tabSet{labels: labelTexts(n)}
+		if blocks := findOwn(n, func(c *html.Node) bool { return htmlutil.HasClass(c, "blocks") }); len(blocks) > 0 {
+			for c := blocks[0].FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode {
+					set.panels = append(set.panels, c)
+				}
+			}
+		}
+		return set.valid()
+	}
+
+	// ARIA tabs: n is the parent of the tablist
+	var tablist *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && htmlutil.Attr(c, "role") == "tablist" {
+			tablist = c
+			break
+		}
+	}
+	if tablist == nil {
+		return nil
+	}
+	tabs := findOwn(tablist, func(c *html.Node) bool { return htmlutil.Attr(c, "role") == "tab" })
+	panels := findOwn(n, func(c *html.Node) bool { return htmlutil.Attr(c, "role") == "tabpanel" })
+	panelByID := make(map[string]*html.Node)
+	for _, p := range panels {
+		if id := htmlutil.Attr(p, "id"); id != "" {
+			panelByID[id] = p
+		}
+	}
+
+	set := &tabSet{}
+	for i, tab := range tabs {
+		panel := panelByID[htmlutil.Attr(tab, "aria-controls")]
+		if panel == nil && i < len(panels) {
+			panel = panels[i]
+		}
+		if panel == nil {
+			continue
+		}
+		set.labels = append(set.labels, htmlutil.Text(tab))
+		set.panels = append(set.panels, panel)
+	}
+	return set.valid()
+}
+
+// valid returns the set if every panel has a label, nil otherwise
+func (s *tabSet) valid() *tabSet {
+	if len(s.panels) == 0 || len(s.labels) != len(s.panels) {
+		return nil
+	}
+	return s
+}
+
+// labelTexts returns the text of the <label> elements of the set n, without
+// those of nested sets
+func labelTexts(n *html.Node) []string {
+	var labels []string
+	for _, l := range findOwn(n, htmlutil.ByTag(atom.Label)) {
+		labels = append(labels, htmlutil.Text(l))
+	}
+	return labels
+}
+
+// findOwn returns the descendants of the set n for which match returns true.
+// It does not look inside matches or nested tab sets, so the tabs of a set
+// shown in one of the panels are left to that set.
+func findOwn(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		htmlutil.Walk(c, func(d *html.Node) bool {
+			if d.Type != html.ElementNode {
+				return true
+			}
+			if match(d) {
+				found = append(found, d)
+				return false
+			}
+			return !isTabSet(d)
+		})
+	}
+	return found
+}
+
+// isTabSet reports whether n is the container of a tab set
+func isTabSet(n *html.Node) bool {
+	if htmlutil.HasClass(n, "tabbed-set") || htmlutil.HasClass(n, "vp-code-group") {
+		return true
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && htmlutil.Attr(c, "role") == "tablist" {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTabs emits each tab as a labeled block, or only the tab matching one
+// of the preferred labels or code languages when configured
+func (r *renderer) renderTabs(set *tabSet) []string {
+	if i := r.preferredTab(set); i >= 0 {
+		return r.renderBlocks(set.panels[i])
+	}
+
+	var blocks []string
+	for i, panel := range set.panels {
+		content := r.renderBlocks(panel)
+		if len(content) == 0 {
+			continue
+		}
+		blocks = append(blocks, set.labels[i]+":")
+		blocks = append(blocks, content...)
+	}
+	return blocks
+}
+
+// preferredTab returns the index of the first tab matching the preferred
+// languages in order of preference, or -1
+func (r *renderer) preferredTab(set *tabSet) int {
+	for _, preferred := range r.opts.PreferredTabs {
+		for i, panel := range set.panels {
+			if strings.EqualFold(set.labels[i], preferred) {
+				return i
+			}
+			if pre := htmlutil.Find(panel, htmlutil.ByTag(atom.Pre)); pre != nil && strings.EqualFold(codeLanguage(pre), preferred) {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,85 @@
+package extractor
+
+import "testing"
+
+const docusaurusTabs = `<div class="tabs-container">
+<ul role="tablist" class="tabs"><li role="tab" class="tabs__item" aria-controls="p-py">Python</li><li role="tab" aria-controls="p-go">Go</li></ul>
+<div class="margin-top--md">
+<div role="tabpanel" id="p-py"><pre><code class="language-python">print("hi")</code></pre></div>
+<div role="tabpanel" id="p-go" hidden><pre><code class="language-go">fmt.Println("hi")</code></pre></div>
+</div></div>`
+
+const vitepressCodeGroup = `<div class="vp-code-group"><div class="tabs">
+<input type="radio" name="group-1" id="tab-1" checked><label for="tab-1">npm</label>
+<input type="radio" name="group-1" id="tab-2"><label for="tab-2">yarn</label>
+</div><div class="blocks">
+<div class="language-sh active"><span class="lang">sh</span><pre><code>npm install</code></pre></div>
+<div class="language-sh"><span class="lang">sh</span><pre><code>yarn add</code></pre></div>
+</div></div>`
+
+const mkdocsTabs = `<div class="tabbed-set tabbed-alternate" data-tabs="1:2">
+<input checked id="__tabbed_1_1" name="__tabbed_1" type="radio"><input id="__tabbed_1_2" name="__tabbed_1" type="radio">
+<div class="tabbed-labels"><label for="__tabbed_1_1">Linux</label><label for="__tabbed_1_2">macOS</label></div>
+<div class="tabbed-content"><div class="tabbed-block"><p>Use apt.</p></div><div class="tabbed-block"><p>Use brew.</p></div></div>
+</div>`
+
+const nestedMkdocsTabs = `<div class="tabbed-set" data-tabs="1:2">
+<div class="tabbed-labels"><label for="__tabbed_1_1">Linux</label><label for="__tabbed_1_2">macOS</label></div>
+<div class="tabbed-content"><div class="tabbed-block">
+<div class="tabbed-set" data-tabs="2:2">
+<div class="tabbed-labels"><label for="__tabbed_2_1">Debian</label><label for="__tabbed_2_2">Fedora</label></div>
+<div class="tabbed-content"><div class="tabbed-block"><p>Use apt.</p></div><div class="tabbed-block"><p>Use dnf.</p></div></div>
+</div>
+</div><div class="tabbed-block"><p>Use brew.</p></div></div>
+</div>`
+
+func TestRenderTabs(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		preferred []string
+		want      string
+	}{
+		{
+			name: "docusaurus",
+			html: docusaurusTabs,
+			want: "Python:\n\n```python\nprint(\"hi\")\n```\n\nGo:\n\n```go\nfmt.Println(\"hi\")\n```",
+		},
+		{
+			name:      "docusaurus preferred by language",
+			html:      docusaurusTabs,
+			preferred: []string{"rust", "go"},
+			want:      "```go\nfmt.Println(\"hi\")\n```",
+		},
+		{
+			name: "vitepress",
+			html: vitepressCodeGroup,
+			want: "npm:\n\n```sh\nnpm install\n```\n\nyarn:\n\n```sh\nyarn add\n```",
+		},
+		{
+			name:      "vitepress preferred by label",
+			html:      vitepressCodeGroup,
+			preferred: []string{"Yarn"},
+			want:      "```sh\nyarn add\n```",
+		},
+		{
+			name:      "mkdocs without a preferred match",
+			html:      mkdocsTabs,
+			preferred: []string{"windows"},
+			want:      "Linux:\n\nUse apt.\n\nmacOS:\n\nUse brew.",
+		},
+		{
+			name: "mkdocs nested",
+			html: nestedMkdocsTabs,
+			want: "Linux:\n\nDebian:\n\nUse apt.\n\nFedora:\n\nUse dnf.\n\nmacOS:\n\nUse brew.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBody(t, tt.html, Options{PreferredTabs: tt.preferred}); got != tt.want {
+				t.Errorf("Body =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
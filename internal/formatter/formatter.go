@@ -4,24 +4,60 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
 )
 
+// OptionalSectionTitle is the title of the section whose links may be skipped
+// when a shorter context is needed, as defined by the llms.txt specification
+const OptionalSectionTitle = "Optional"
+
 // FormatOptions contains options for formatting the LLMsTXT output
 type FormatOptions struct {
 	ProjectName      string
 	Summary          string
 	GeneralInfo      string
 	OrganizationInfo string
+	// SectionTitles overrides the display title of section keys. Keys of nested
+	// sections ("guides/advanced") may be overridden as a whole or per level.
+	SectionTitles map[string]string
+	// SectionOrder lists section keys in reading order. Sections not listed
+	// follow in alphabetical order.
+	SectionOrder []string
+	// TokenBudget caps the estimated token count of the output. When it is
+	// exceeded, detailed content of optional pages is left out (0 = unlimited).
+	// Required content is always written, so the output can still exceed it.
+	TokenBudget int
+	// MaxHeadingLevel is the deepest heading level kept inside page bodies;
+	// deeper headings become bold paragraphs (0 = 6)
+	MaxHeadingLevel int
+	// Subsections adds a sub-bullet linking to each top-level heading with an
+	// anchor below the entry of its page
+	Subsections bool
 }
 
 // ExtractedContent represents the extracted content from an HTML file
 type ExtractedContent struct {
-	FilePath    string // Original file path
-	URL         string // URL (from sitemap or generated from file path)
-	Title       string // Extracted title
-	TextContent string // Extracted plain text content
-	Excerpt     string // Extracted summary/excerpt
-	Section     string // Determined section based on directory structure
+	FilePath    string            `json:"filePath"`           // Original file path
+	URL         string            `json:"url"`                // URL (from sitemap or generated from file path)
+	Title       string            `json:"title"`              // Extracted title
+	TextContent string            `json:"textContent"`        // Extracted plain text content
+	Excerpt     string            `json:"excerpt"`            // Extracted summary/excerpt
+	Section     string            `json:"section"`            // Determined section based on directory structure
+	Locale      string            `json:"locale,omitempty"`   // Detected locale, e.g. "ja"; empty when unknown
+	Optional    bool              `json:"optional,omitempty"` // Listed in the trailing "## Optional" section
+	Order       int               `json:"order,omitempty"`    // Position within its section (1-based); 0 sorts after ordered pages by title
+	Metadata    map[string]string `json:"metadata,omitempty"` // Page metadata (OpenGraph, meta tags, JSON-LD), see extractor.Meta* keys
+	Headings    []Heading         `json:"headings,omitempty"` // Headings of TextContent, in order
+}
+
+// Heading is a heading inside the content of a page
+type Heading struct {
+	Level int    `json:"level"`        // 1-6, as in the original page
+	Text  string `json:"text"`         // Plain text of the heading
+	ID    string `json:"id,omitempty"` // Anchor of the heading in the page
 }
 
 // DefaultFormatOptions returns default format options
@@ -53,15 +89,25 @@ func FormatLLMsTXTWithOptions(contents []ExtractedContent, options FormatOptions
 	sb.WriteString(fmt.Sprintf("%s\n\n", options.GeneralInfo))
 	sb.WriteString(fmt.Sprintf("%s\n\n", options.OrganizationInfo))
 
+	// Optional pages are emitted after all other sections
+	var required, optional []ExtractedContent
+	for _, content := range contents {
+		if content.Optional {
+			optional = append(optional, content)
+		} else {
+			required = append(required, content)
+		}
+	}
+
 	// Group contents by section
-	sectionMap := groupBySection(contents)
+	sectionMap := groupBySection(required)
 
 	// Sort sections
 	var sections []string
 	for section := range sectionMap {
 		sections = append(sections, section)
 	}
-	sort.Strings(sections)
+	sortSections(sections, options.SectionOrder)
 
 	// Process each section
 	for _, section := range sections {
@@ -72,62 +118,147 @@ func FormatLLMsTXTWithOptions(contents []ExtractedContent, options FormatOptions
 			continue
 		}
 
-		// Sort contents to match expected order in tests
-		// In this case, we want "Test Document" to come before "Another Test Document"
-		sort.Slice(sectionContents, func(i, j int) bool {
-			// Special case for the test files
-			if sectionContents[i].Title == "Test Document" && sectionContents[j].Title == "Another Test Document" {
-				return true
-			}
-			if sectionContents[i].Title == "Another Test Document" && sectionContents[j].Title == "Test Document" {
-				return false
-			}
-			// Default to alphabetical order by title
-			return sectionContents[i].Title < sectionContents[j].Title
-		})
+		sortContents(sectionContents)
 
 		// Add section header
-		formattedTitle := formatSectionTitle(section)
+		formattedTitle := SectionTitle(section, options.SectionTitles)
 		sb.WriteString(fmt.Sprintf("## %s\n\n", formattedTitle))
 
 		// Add file list for this section
-		for _, content := range sectionContents {
-			// Create a URL-friendly path
-			// Use the URL field from ExtractedContent directly
-			urlPath := content.URL
-
-			// Add the file entry
-			// Ensure the URL has a single leading slash
-			formattedUrlPath := urlPath
-			if strings.HasPrefix(formattedUrlPath, "/") {
-				// URL already has a leading slash, use as is
-			} else {
-				// Add a leading slash
-				formattedUrlPath = "/" + formattedUrlPath
-			}
-
-			sb.WriteString(fmt.Sprintf("- [%s](%s): %s\n",
-				content.Title,
-				formattedUrlPath,
-				content.Excerpt)) // Use Excerpt for summary
-		}
+		writeLinkList(&sb, sectionContents, options)
 		sb.WriteString("\n")
 
 		// Add detailed content for this section
 		sb.WriteString("\n") // Add extra newline before detailed content
 
 		for _, content := range sectionContents {
-			sb.WriteString(fmt.Sprintf("### %s\n\n", content.Title))
-			// Add title as the first line of the content, followed by the actual content
-			sb.WriteString(content.Title + "\n")
-			sb.WriteString(content.TextContent) // Use TextContent
-			sb.WriteString("\n\n---\n\n")
+			writeDetails(&sb, content, options)
+		}
+	}
+
+	if len(optional) > 0 {
+		sortContents(optional)
+
+		sb.WriteString(fmt.Sprintf("## %s\n\n", OptionalSectionTitle))
+		writeLinkList(&sb, optional, options)
+		sb.WriteString("\n")
+		sb.WriteString("\n")
+
+		// Detailed content of optional pages is the first thing dropped
+		// when the output would exceed the token budget
+		used := utils.EstimateTokens(sb.String())
+		for _, content := range optional {
+			var details strings.Builder
+			writeDetails(&details, content, options)
+			tokens := utils.EstimateTokens(details.String())
+			if options.TokenBudget > 0 && used+tokens > options.TokenBudget {
+				continue
+			}
+			sb.WriteString(details.String())
+			used += tokens
 		}
 	}
 
 	return sb.String()
 }
 
+// sortSections sorts section keys by their position in order, placing
+// unlisted sections after listed ones in alphabetical order
+func sortSections(sections []string, order []string) {
+	position := make(map[string]int, len(order))
+	for i, section := range order {
+		if _, ok := position[section]; !ok {
+			position[section] = i
+		}
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		pi, oki := position[sections[i]]
+		pj, okj := position[sections[j]]
+		if oki != okj {
+			return oki
+		}
+		if oki && pi != pj {
+			return pi < pj
+		}
+		return sections[i] < sections[j]
+	})
+}
+
+// sortContents sorts the pages of a section by their explicit order, placing
+// unordered pages after ordered ones in alphabetical order by title
+func sortContents(contents []ExtractedContent) {
+	sort.SliceStable(contents, func(i, j int) bool {
+		oi, oj := contents[i].Order, contents[j].Order
+		if (oi > 0) != (oj > 0) {
+			return oi > 0
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return contents[i].Title < contents[j].Title
+	})
+}
+
+// writeLinkList writes the "- [Title](url): notes" entries for a list of pages
+func writeLinkList(sb *strings.Builder, contents []ExtractedContent, options FormatOptions) {
+	for _, content := range contents {
+		// Create a URL-friendly path
+		// Use the URL field from ExtractedContent directly
+		urlPath := content.URL
+
+		// Add the file entry
+		// Ensure the URL has a single leading slash
+		formattedUrlPath := urlPath
+		if strings.HasPrefix(formattedUrlPath, "/") {
+			// URL already has a leading slash, use as is
+		} else {
+			// Add a leading slash
+			formattedUrlPath = "/" + formattedUrlPath
+		}
+
+		sb.WriteString(fmt.Sprintf("- [%s](%s): %s\n",
+			content.Title,
+			formattedUrlPath,
+			content.Excerpt)) // Use Excerpt for summary
+
+		if options.Subsections {
+			for _, h := range subsections(content.Headings) {
+				sb.WriteString(fmt.Sprintf("  - [%s%s%s](%s#%s)\n",
+					content.Title, SectionLevelSeparator, h.Text, formattedUrlPath, h.ID))
+			}
+		}
+	}
+}
+
+// writeDetails writes the detailed content block of a page, if its body is not empty
+func writeDetails(sb *strings.Builder, content ExtractedContent, options FormatOptions) {
+	body := normalizeHeadings(content.TextContent, content.Title, options.MaxHeadingLevel)
+	if strings.TrimSpace(body) == "" {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("#", PageHeadingLevel), content.Title))
+	sb.WriteString(body)
+	sb.WriteString("\n\n---\n\n")
+}
+
+// subsections returns the headings with an anchor at the top level of a page
+// body, which is h2 on most sites
+func subsections(headings []Heading) []Heading {
+	top := 0
+	for _, h := range headings {
+		if h.ID != "" && (top == 0 || h.Level < top) {
+			top = h.Level
+		}
+	}
+	var result []Heading
+	for _, h := range headings {
+		if h.ID != "" && h.Level == top {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
 // groupBySection groups the parsed content by section
 func groupBySection(contents []ExtractedContent) map[string][]ExtractedContent {
 	sectionMap := make(map[string][]ExtractedContent)
@@ -140,28 +271,39 @@ func groupBySection(contents []ExtractedContent) map[string][]ExtractedContent {
 	return sectionMap
 }
 
-// formatSectionTitle formats a section title
-func formatSectionTitle(section string) string {
-	// Handle special cases
-	switch section {
-	case "action":
-		return "Actions"
-	case "view":
-		return "Views"
-	case "admin":
-		return "Administration"
-	case "faq":
-		return "FAQ"
-	case "tips":
-		return "Tips and Tricks"
-	default:
-		// Capitalize the first letter of each word
-		words := strings.Split(section, "_")
-		for i, word := range words {
-			if len(word) > 0 {
-				words[i] = strings.ToUpper(word[0:1]) + word[1:]
-			}
+// SectionLevelSeparator joins the levels of a nested section title
+const SectionLevelSeparator = " › "
+
+// SectionTitle returns the display title of a section key. A nested key such as
+// "guides/advanced" becomes "Guides › Advanced", with each level looked up in
+// titles by its full prefix before falling back to formatSectionTitle.
+func SectionTitle(section string, titles map[string]string) string {
+	if title, ok := titles[section]; ok {
+		return title
+	}
+
+	levels := strings.Split(strings.Trim(section, "/"), "/")
+	parts := make([]string, 0, len(levels))
+	for i, level := range levels {
+		prefix := strings.Join(levels[:i+1], "/")
+		if title, ok := titles[prefix]; ok {
+			parts = append(parts, title)
+		} else {
+			parts = append(parts, formatSectionTitle(level))
 		}
-		return strings.Join(words, " ")
 	}
+	return strings.Join(parts, SectionLevelSeparator)
+}
+
+// formatSectionTitle formats a single section level, turning "getting-started"
+// or "getting_started" into "Getting Started"
+func formatSectionTitle(section string) string {
+	words := strings.FieldsFunc(section, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
 }
@@ -192,48 +192,306 @@ func TestFormatSectionTitle(t *testing.T) {
 		want    string
 	}{
 		{
-			name:    "Special case: action",
-			section: "action",
-			want:    "Actions",
+			name:    "Regular case: single word",
+			section: "section",
+			want:    "Section",
 		},
 		{
-			name:    "Special case: view",
-			section: "view",
-			want:    "Views",
+			name:    "Regular case: multiple words with underscore",
+			section: "getting_started",
+			want:    "Getting Started",
 		},
 		{
-			name:    "Special case: admin",
-			section: "admin",
-			want:    "Administration",
+			name:    "Regular case: multiple words with hyphen",
+			section: "getting-started",
+			want:    "Getting Started",
 		},
 		{
-			name:    "Special case: faq",
+			name:    "Regular case: no special casing",
+			section: "faq",
+			want:    "Faq",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatSectionTitle(tt.section)
+			if got != tt.want {
+				t.Errorf("formatSectionTitle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionTitle(t *testing.T) {
+	titles := map[string]string{
+		"faq":             "FAQ",
+		"api":             "API Reference",
+		"guides/advanced": "Advanced Topics",
+	}
+
+	tests := []struct {
+		name    string
+		section string
+		want    string
+	}{
+		{
+			name:    "Configured title",
 			section: "faq",
 			want:    "FAQ",
 		},
 		{
-			name:    "Special case: tips",
-			section: "tips",
-			want:    "Tips and Tricks",
+			name:    "Nested section",
+			section: "guides/getting-started",
+			want:    "Guides › Getting Started",
 		},
 		{
-			name:    "Regular case: single word",
-			section: "section",
-			want:    "Section",
+			name:    "Nested section with configured level",
+			section: "api/v2_beta",
+			want:    "API Reference › V2 Beta",
 		},
 		{
-			name:    "Regular case: multiple words with underscore",
-			section: "getting_started",
-			want:    "Getting Started",
+			name:    "Configured nested section",
+			section: "guides/advanced",
+			want:    "Advanced Topics",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := formatSectionTitle(tt.section)
+			got := SectionTitle(tt.section, titles)
 			if got != tt.want {
-				t.Errorf("formatSectionTitle() = %v, want %v", got, tt.want)
+				t.Errorf("SectionTitle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLLMsTXTOptionalSection(t *testing.T) {
+	contents := []ExtractedContent{
+		{
+			Title:       "Getting Started",
+			TextContent: "Install the tool.",
+			URL:         "/guide/start",
+			Excerpt:     "How to start",
+			Section:     "guide",
+		},
+		{
+			Title:       "Release 1.0",
+			TextContent: strings.Repeat("Changelog entry. ", 100),
+			URL:         "/changelog/v1",
+			Excerpt:     "Release notes",
+			Section:     "changelog",
+			Optional:    true,
+		},
+	}
+
+	result := FormatLLMsTXTWithOptions(contents, DefaultFormatOptions("Test Project"))
+
+	if strings.Contains(result, "## Changelog") {
+		t.Errorf("Optional page should not get its own section: %s", result)
+	}
+	optionalIdx := strings.Index(result, "## Optional")
+	if optionalIdx < 0 {
+		t.Fatalf("Optional section not included in the output: %s", result)
+	}
+	if optionalIdx < strings.Index(result, "## Guide") {
+		t.Errorf("Optional section should come after all other sections: %s", result)
+	}
+	if !strings.Contains(result[optionalIdx:], "- [Release 1.0](/changelog/v1): Release notes") {
+		t.Errorf("Optional link not listed under Optional section: %s", result)
+	}
+	if !strings.Contains(result, "### Release 1.0") {
+		t.Errorf("Optional page content should be included without a budget: %s", result)
+	}
+
+	// With a tight budget the optional link stays but its content is dropped
+	options := DefaultFormatOptions("Test Project")
+	options.TokenBudget = 200
+	result = FormatLLMsTXTWithOptions(contents, options)
+
+	if !strings.Contains(result, "- [Release 1.0](/changelog/v1): Release notes") {
+		t.Errorf("Optional link should be kept under a token budget: %s", result)
+	}
+	if strings.Contains(result, "### Release 1.0") {
+		t.Errorf("Optional page content should be dropped under a token budget: %s", result)
+	}
+	if !strings.Contains(result, "### Getting Started") {
+		t.Errorf("Required page content should never be dropped: %s", result)
+	}
+}
+
+func TestFormatLLMsTXTEmptyBody(t *testing.T) {
+	contents := []ExtractedContent{
+		{Title: "Overview", TextContent: "The overview.", URL: "/overview", Section: "guide"},
+		{Title: "Redirect", TextContent: "# Redirect\n\n", URL: "/redirect", Section: "guide"},
+	}
+
+	result := FormatLLMsTXTWithOptions(contents, DefaultFormatOptions("Test Project"))
+
+	if !strings.Contains(result, "- [Redirect](/redirect): ") {
+		t.Errorf("Page with an empty body should still be listed: %s", result)
+	}
+	if strings.Contains(result, "### Redirect") {
+		t.Errorf("Page with an empty body should not get a detail block: %s", result)
+	}
+	if strings.Count(result, "---") != 1 {
+		t.Errorf("Expected a single detail block: %s", result)
+	}
+}
+
+func TestFormatLLMsTXTOrdering(t *testing.T) {
+	contents := []ExtractedContent{
+		{Title: "API Overview", URL: "/api/overview", Section: "api"},
+		{Title: "Zeta Feature", URL: "/guide/zeta", Section: "guide", Order: 1},
+		{Title: "Alpha Feature", URL: "/guide/alpha", Section: "guide", Order: 2},
+		{Title: "Appendix", URL: "/guide/appendix", Section: "guide"},
+		{Title: "Changelog", URL: "/changelog", Section: "changelog"},
+	}
+
+	options := DefaultFormatOptions("Test Project")
+	options.SectionOrder = []string{"guide", "api"}
+	result := FormatLLMsTXTWithOptions(contents, options)
+
+	assertOrder := func(items ...string) {
+		t.Helper()
+		last := -1
+		for _, item := range items {
+			idx := strings.Index(result, item)
+			if idx < 0 {
+				t.Fatalf("%q not found in output: %s", item, result)
+			}
+			if idx < last {
+				t.Errorf("%q is out of order in output: %s", item, result)
+			}
+			last = idx
+		}
+	}
+
+	assertOrder("## Guide", "## Api", "## Changelog")
+	assertOrder("- [Zeta Feature]", "- [Alpha Feature]", "- [Appendix]")
+}
+
+func TestNormalizeHeadings(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		title    string
+		maxLevel int
+		want     string
+	}{
+		{
+			name:  "duplicate title dropped and headings demoted",
+			body:  "# Install\n\nIntro.\n\n## Linux\n\n### Debian\n\n## macOS",
+			title: "Install",
+			want:  "Intro.\n\n#### Linux\n\n##### Debian\n\n#### macOS",
+		},
+		{
+			name:  "headings already below the page heading",
+			body:  "#### Usage\n\nText.",
+			title: "Guide",
+			want:  "#### Usage\n\nText.",
+		},
+		{
+			name:     "max depth",
+			body:     "## Options\n\n### Advanced\n\n#### Internals",
+			title:    "Config",
+			maxLevel: 5,
+			want:     "#### Options\n\n##### Advanced\n\n**Internals**",
+		},
+		{
+			name:  "fenced code untouched",
+			body:  "## Script\n\n```sh\n# comment\n```",
+			title: "Tools",
+			want:  "#### Script\n\n```sh\n# comment\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeHeadings(tt.body, tt.title, tt.maxLevel); got != tt.want {
+				t.Errorf("normalizeHeadings() =\n%s\nwant\n%s", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestFormatLLMsTXTSubsections(t *testing.T) {
+	contents := []ExtractedContent{{
+		Title:   "Install",
+		URL:     "/guide/install",
+		Section: "guide",
+		Excerpt: "How to install.",
+		Headings: []Heading{
+			{Level: 2, Text: "Linux", ID: "linux"},
+			{Level: 3, Text: "Debian", ID: "debian"},
+			{Level: 2, Text: "No anchor"},
+			{Level: 2, Text: "macOS", ID: "macos"},
+		},
+	}}
+
+	options := DefaultFormatOptions("Test Project")
+	options.Subsections = true
+	result := FormatLLMsTXTWithOptions(contents, options)
+
+	want := "- [Install](/guide/install): How to install.\n" +
+		"  - [Install › Linux](/guide/install#linux)\n" +
+		"  - [Install › macOS](/guide/install#macos)\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("Expected sub-bullets per h2:\n%s\nin output:\n%s", want, result)
+	}
+
+	options.Subsections = false
+	if result := FormatLLMsTXTWithOptions(contents, options); strings.Contains(result, "#linux") {
+		t.Errorf("Sub-bullets should only be listed with Subsections: %s", result)
+	}
+}
+
+func TestParse(t *testing.T) {
+	contents := []ExtractedContent{
+		{Title: "Install", URL: "/guide/install", Section: "guide", Excerpt: "How to install.",
+			TextContent: "Run:\n\n```md\n## Not a section\n\n---\n```\n\n## Linux\n\nUse apt."},
+		{Title: "API", URL: "/api", Section: "api", Excerpt: ""},
+		{Title: "Changelog", URL: "/changelog", Section: "changelog", Optional: true, Excerpt: "Releases."},
+	}
+	options := DefaultFormatOptions("Test Project")
+	options.SectionOrder = []string{"guide", "api"}
+	options.Subsections = true
+
+	doc, err := Parse(FormatLLMsTXTWithOptions(contents, options))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if doc.ProjectName != "Test Project" || doc.Summary != options.Summary {
+		t.Errorf("header = %q, %q", doc.ProjectName, doc.Summary)
+	}
+
+	var titles []string
+	for _, s := range doc.Sections {
+		titles = append(titles, s.Title)
+		if s.Tokens == 0 {
+			t.Errorf("section %s has no tokens", s.Title)
+		}
+	}
+	if got := strings.Join(titles, ","); got != "Guide,Api,Optional" {
+		t.Fatalf("sections = %s", got)
+	}
+
+	install := doc.Sections[0].Pages[0]
+	if install.Title != "Install" || install.URL != "/guide/install" || install.Notes != "How to install." {
+		t.Errorf("page = %+v", install)
+	}
+	if want := "Run:\n\n```md\n## Not a section\n\n---\n```\n\n#### Linux\n\nUse apt."; install.Details != want {
+		t.Errorf("details =\n%s\nwant\n%s", install.Details, want)
+	}
+	if api := doc.Sections[1].Pages[0]; api.URL != "/api" || api.Notes != "" {
+		t.Errorf("page = %+v", api)
+	}
+	if changelog := doc.Sections[2].Pages[0]; changelog.Title != "Changelog" || changelog.Notes != "Releases." {
+		t.Errorf("page = %+v", changelog)
+	}
+
+	if _, err := Parse("no heading here"); err == nil {
+		t.Error("Parse() of text without a project name should fail")
+	}
+}
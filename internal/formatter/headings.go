@@ -0,0 +1,84 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PageHeadingLevel is the Markdown heading level of each page in the detailed content
+const PageHeadingLevel = 3
+
+// maxMarkdownHeadingLevel is the deepest heading level Markdown supports
+const maxMarkdownHeadingLevel = 6
+
+// atxHeading matches a Markdown ATX heading line
+var atxHeading = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+// normalizeHeadings fits the headings of a page body below the page heading:
+// a leading heading repeating title is dropped, the remaining headings are
+// shifted so the shallowest one sits one level below PageHeadingLevel, and
+// headings deeper than maxLevel become bold paragraphs. Fenced code is left alone.
+func normalizeHeadings(body, title string, maxLevel int) string {
+	if maxLevel <= 0 || maxLevel > maxMarkdownHeadingLevel {
+		maxLevel = maxMarkdownHeadingLevel
+	}
+	lines := strings.Split(strings.TrimLeft(body, "\n"), "\n")
+
+	// Drop a leading heading that duplicates the page title
+	if m := atxHeading.FindStringSubmatch(lines[0]); m != nil && strings.EqualFold(strings.TrimSpace(m[2]), strings.TrimSpace(title)) {
+		lines = lines[1:]
+		for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+			lines = lines[1:]
+		}
+	}
+
+	headings := headingLines(lines)
+	minLevel := 0
+	for _, i := range headings {
+		level := len(atxHeading.FindStringSubmatch(lines[i])[1])
+		if minLevel == 0 || level < minLevel {
+			minLevel = level
+		}
+	}
+	shift := PageHeadingLevel + 1 - minLevel
+	if shift < 0 {
+		shift = 0
+	}
+
+	for _, i := range headings {
+		m := atxHeading.FindStringSubmatch(lines[i])
+		level := len(m[1]) + shift
+		if level > maxLevel {
+			lines[i] = "**" + m[2] + "**"
+		} else {
+			lines[i] = strings.Repeat("#", level) + " " + m[2]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// headingLines returns the indexes of heading lines outside fenced code blocks
+func headingLines(lines []string) []int {
+	var indexes []int
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			for len(fence) < len(trimmed) && trimmed[len(fence)] == fence[0] {
+				fence += fence[:1]
+			}
+			continue
+		}
+		if atxHeading.MatchString(line) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,127 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// Document is an llms.txt file read back by Parse
+type Document struct {
+	ProjectName string
+	Summary     string
+	Sections    []ParsedSection
+}
+
+// ParsedSection is a "## " section of a Document
+type ParsedSection struct {
+	Title  string
+	Pages  []ParsedPage
+	Tokens int // Estimated tokens of the section, including detailed content
+}
+
+// ParsedPage is a link entry of a section with its detailed content
+type ParsedPage struct {
+	Title   string
+	URL     string
+	Notes   string
+	Details string // Content below the "### " heading of the page, empty when left out
+}
+
+var (
+	linkEntry     = regexp.MustCompile(`^- \[(.*)\]\(([^)\s]*)\)(?::\s?(.*))?$`)
+	detailsEnd    = "---"
+	codeFenceLine = regexp.MustCompile("^\\s*(```+|~~~+)")
+)
+
+// Parse reads llms.txt content in the layout written by FormatLLMsTXTWithOptions:
+// an H1 project name, a blockquote summary, then "## " sections listing
+// "- [Title](url): notes" entries, each followed by "### Title" blocks ending
+// with "---". Lines that do not fit the layout are ignored.
+func Parse(text string) (*Document, error) {
+	doc := &Document{}
+	var section *ParsedSection
+	var sectionText strings.Builder
+	var page *ParsedPage // Page whose details are being read
+	var details []string
+	fence := ""
+
+	endSection := func() {
+		if section != nil {
+			section.Tokens = utils.EstimateTokens(sectionText.String())
+			doc.Sections = append(doc.Sections, *section)
+		}
+		section = nil
+		sectionText.Reset()
+	}
+	endDetails := func() {
+		if page != nil {
+			page.Details = strings.TrimSpace(strings.Join(details, "\n"))
+		}
+		page, details = nil, nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if section != nil {
+			sectionText.WriteString(line + "\n")
+		}
+
+		// Inside code, nothing ends the details
+		if m := codeFenceLine.FindStringSubmatch(line); m != nil {
+			if fence == "" {
+				fence = m[1]
+			} else if strings.HasPrefix(m[1], fence) && strings.TrimSpace(line) == m[1] {
+				fence = ""
+			}
+		} else if fence == "" {
+			switch {
+			case strings.HasPrefix(line, "# ") && doc.ProjectName == "" && section == nil:
+				doc.ProjectName = strings.TrimSpace(line[2:])
+				continue
+			case strings.HasPrefix(line, "> ") && doc.Summary == "" && section == nil:
+				doc.Summary = strings.TrimSpace(line[2:])
+				continue
+			case strings.HasPrefix(line, "## "):
+				endDetails()
+				endSection()
+				section = &ParsedSection{Title: strings.TrimSpace(line[3:])}
+				sectionText.WriteString(line + "\n")
+				continue
+			case strings.HasPrefix(line, "### ") && section != nil:
+				endDetails()
+				page = section.findPage(strings.TrimSpace(line[4:]))
+				continue
+			case strings.TrimSpace(line) == detailsEnd && page != nil:
+				endDetails()
+				continue
+			case page == nil && section != nil:
+				if m := linkEntry.FindStringSubmatch(line); m != nil {
+					section.Pages = append(section.Pages, ParsedPage{Title: m[1], URL: m[2], Notes: strings.TrimSpace(m[3])})
+				}
+				continue
+			}
+		}
+		if page != nil {
+			details = append(details, line)
+		}
+	}
+	endDetails()
+	endSection()
+
+	if doc.ProjectName == "" {
+		return nil, fmt.Errorf("not an llms.txt file: missing \"# \" project name heading")
+	}
+	return doc, nil
+}
+
+// findPage returns the first page titled title whose details were not read yet
+func (s *ParsedSection) findPage(title string) *ParsedPage {
+	for i := range s.Pages {
+		if s.Pages[i].Title == title && s.Pages[i].Details == "" {
+			return &s.Pages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package htmlutil provides small helpers for walking parsed HTML documents
+package htmlutil
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Attr returns the value of an attribute, or an empty string if it is not set
+func Attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// HasAttr reports whether an element has an attribute
+func HasAttr(n *html.Node, name string) bool {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Classes returns the class names of an element
+func Classes(n *html.Node) []string {
+	return strings.Fields(Attr(n, "class"))
+}
+
+// HasClass reports whether an element has the given class
+func HasClass(n *html.Node, class string) bool {
+	for _, c := range Classes(n) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// IsElement reports whether n is an element of the given type
+func IsElement(n *html.Node, a atom.Atom) bool {
+	return n != nil && n.Type == html.ElementNode && n.DataAtom == a
+}
+
+// Text returns the text content of a node with whitespace collapsed
+func Text(n *html.Node) string {
+	return strings.Join(strings.Fields(RawText(n)), " ")
+}
+
+// RawText returns the text content of a node exactly as it appears in the
+// document. Scripts and styles below n are skipped.
+func RawText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+			return
+		}
+		if c != n && c.Type == html.ElementNode && (c.DataAtom == atom.Script || c.DataAtom == atom.Style) {
+			return
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// Walk calls fn for n and its descendants in document order. Returning false
+// from fn skips the children of that node.
+func Walk(n *html.Node, fn func(*html.Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		Walk(c, fn)
+	}
+}
+
+// Find returns the first descendant element (including n itself) for which match returns true
+func Find(n *html.Node, match func(*html.Node) bool) *html.Node {
+	var found *html.Node
+	Walk(n, func(c *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if c.Type == html.ElementNode && match(c) {
+			found = c
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// FindAll returns all descendant elements (including n itself) for which match returns true
+func FindAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	Walk(n, func(c *html.Node) bool {
+		if c.Type == html.ElementNode && match(c) {
+			found = append(found, c)
+		}
+		return true
+	})
+	return found
+}
+
+// ByTag returns a matcher for elements of the given type
+func ByTag(a atom.Atom) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return n.DataAtom == a
+	}
+}
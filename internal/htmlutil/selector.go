@@ -0,0 +1,236 @@
+package htmlutil
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Selector is a compiled subset of CSS selectors: type, #id, .class and
+// [attr], [attr=v], [attr^=v], [attr$=v], [attr*=v], [attr~=v] compounds,
+// joined by descendant (" ") or child (">") combinators, in comma-separated groups.
+type Selector struct {
+	groups [][]compound
+}
+
+// compound is one step of a selector, such as "nav.sidebar[data-x]"
+type compound struct {
+	tag        string
+	id         string
+	classes    []string
+	attrs      []attrMatcher
+	combinator byte // Relation to the previous compound: ' ' or '>'
+}
+
+type attrMatcher struct {
+	name, op, value string
+}
+
+// Compile parses a selector
+func Compile(s string) (*Selector, error) {
+	sel := &Selector{}
+	for _, group := range strings.Split(s, ",") {
+		steps, err := parseGroup(strings.TrimSpace(group))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+		}
+		sel.groups = append(sel.groups, steps)
+	}
+	return sel, nil
+}
+
+// MustCompile is like Compile but panics on invalid selectors
+func MustCompile(s string) *Selector {
+	sel, err := Compile(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+// Match reports whether an element matches the selector
+func (s *Selector) Match(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	for _, steps := range s.groups {
+		if matchSteps(n, steps) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query returns the first element under root (including root) matching the selector
+func (s *Selector) Query(root *html.Node) *html.Node {
+	return Find(root, s.Match)
+}
+
+// QueryAll returns all elements under root (including root) matching the selector
+func (s *Selector) QueryAll(root *html.Node) []*html.Node {
+	return FindAll(root, s.Match)
+}
+
+func parseGroup(s string) ([]compound, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+	var steps []compound
+	combinator := byte(' ')
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case ' ', '\t', '\n':
+			i++
+			continue
+		case '>':
+			combinator = '>'
+			i++
+			continue
+		}
+		c, n, err := parseCompound(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		combinator = ' '
+		steps = append(steps, c)
+		i += n
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+	return steps, nil
+}
+
+func parseCompound(s string) (compound, int, error) {
+	var c compound
+	i := 0
+	ident := func() string {
+		start := i
+		for i < len(s) && (isIdentChar(s[i])) {
+			i++
+		}
+		return s[start:i]
+	}
+
+	if i < len(s) && s[i] == '*' {
+		i++
+	} else if i < len(s) && isIdentChar(s[i]) {
+		c.tag = strings.ToLower(ident())
+	}
+	for i < len(s) {
+		switch s[i] {
+		case '#':
+			i++
+			c.id = ident()
+		case '.':
+			i++
+			c.classes = append(c.classes, ident())
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return c, 0, fmt.Errorf("unterminated attribute selector")
+			}
+			a, err := parseAttr(s[i+1 : i+end])
+			if err != nil {
+				return c, 0, err
+			}
+			c.attrs = append(c.attrs, a)
+			i += end + 1
+		case ' ', '\t', '\n', '>':
+			return c, i, nil
+		default:
+			return c, 0, fmt.Errorf("unexpected %q", s[i])
+		}
+	}
+	return c, i, nil
+}
+
+func parseAttr(s string) (attrMatcher, error) {
+	for _, op := range []string{"^=", "$=", "*=", "~=", "="} {
+		if idx := strings.Index(s, op); idx >= 0 {
+			value := strings.Trim(strings.TrimSpace(s[idx+len(op):]), `"'`)
+			return attrMatcher{name: strings.TrimSpace(s[:idx]), op: op, value: value}, nil
+		}
+	}
+	name := strings.TrimSpace(s)
+	if name == "" {
+		return attrMatcher{}, fmt.Errorf("empty attribute selector")
+	}
+	return attrMatcher{name: name}, nil
+}
+
+func isIdentChar(b byte) bool {
+	return b == '-' || b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
+
+// matchSteps matches the last step against n and the earlier steps against its ancestors
+func matchSteps(n *html.Node, steps []compound) bool {
+	last := steps[len(steps)-1]
+	if !last.match(n) {
+		return false
+	}
+	if len(steps) == 1 {
+		return true
+	}
+	rest := steps[:len(steps)-1]
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		if matchSteps(p, rest) {
+			return true
+		}
+		if last.combinator == '>' {
+			return false
+		}
+	}
+	return false
+}
+
+func (c compound) match(n *html.Node) bool {
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	if c.id != "" && Attr(n, "id") != c.id {
+		return false
+	}
+	for _, class := range c.classes {
+		if !HasClass(n, class) {
+			return false
+		}
+	}
+	for _, a := range c.attrs {
+		if !HasAttr(n, a.name) {
+			return false
+		}
+		v := Attr(n, a.name)
+		switch a.op {
+		case "=":
+			if v != a.value {
+				return false
+			}
+		case "^=":
+			if !strings.HasPrefix(v, a.value) {
+				return false
+			}
+		case "$=":
+			if !strings.HasSuffix(v, a.value) {
+				return false
+			}
+		case "*=":
+			if !strings.Contains(v, a.value) {
+				return false
+			}
+		case "~=":
+			found := false
+			for _, f := range strings.Fields(v) {
+				if f == a.value {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package htmlutil
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestSelector(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><body>
+		<nav id="sidebar" class="menu theme-doc-sidebar">
+			<ul><li><a class="menu__link active" href="/a" data-lang="go">A</a></li></ul>
+		</nav>
+		<div class="content"><p><a href="https://example.com/b">B</a></p></div>
+	</body></html>`))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"a", []string{"A", "B"}},
+		{"nav a", []string{"A"}},
+		{"#sidebar a.menu__link", []string{"A"}},
+		{"nav.menu.theme-doc-sidebar > ul a", []string{"A"}},
+		{"nav > a", nil},
+		{"div > p > a", []string{"B"}},
+		{"a[href^=https]", []string{"B"}},
+		{"a[class~=active]", []string{"A"}},
+		{"[data-lang=\"go\"]", []string{"A"}},
+		{".content a, #sidebar a", []string{"A", "B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, err := Compile(tt.selector)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			var got []string
+			for _, n := range sel.QueryAll(doc) {
+				got = append(got, Text(n))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("QueryAll(%q) = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, s := range []string{"", "a[href", "a,", "a!b"} {
+		if _, err := Compile(s); err == nil {
+			t.Errorf("Compile(%q) should fail", s)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package linkcheck verifies that links emitted into llms.txt resolve to a page
+package linkcheck
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// Problem describes a single broken link
+type Problem struct {
+	Source string // File the link was found in
+	Line   int    // 1-based line within Source, 0 when unknown
+	Link   string // Link target as written
+	Reason string // Why the link is considered broken
+}
+
+// String formats the problem as "source:line: link: reason"
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %s", p.Source, p.Line, p.Link, p.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Source, p.Link, p.Reason)
+}
+
+// Checker resolves links against the set of processed pages and, optionally, a live site
+type Checker struct {
+	BaseURL string       // Base URL of the published site (optional)
+	Remote  bool         // Fall back to an HTTP request at BaseURL for unknown links
+	Client  *http.Client // HTTP client used for remote checks
+
+	pages  map[string]bool
+	remote map[string]error
+}
+
+// New creates a Checker for the given base URL
+func New(baseURL string, remote bool) *Checker {
+	return &Checker{
+		BaseURL: baseURL,
+		Remote:  remote,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		pages:   make(map[string]bool),
+		remote:  make(map[string]error),
+	}
+}
+
+// AddPage registers the URL path of a processed page
+func (c *Checker) AddPage(urlPath string) {
+	c.pages[utils.NormalizeURLPath(urlPath)] = true
+}
+
+// CheckLink verifies a single link found in source at the given line.
+// Relative links are resolved against pageURL. It returns nil when the link resolves.
+func (c *Checker) CheckLink(source string, line int, pageURL, link string) *Problem {
+	target, local, err := c.resolve(pageURL, link)
+	if err != nil {
+		return &Problem{Source: source, Line: line, Link: link, Reason: err.Error()}
+	}
+	if target == "" {
+		// Anchors, mailto: and similar links are not checked
+		return nil
+	}
+
+	if local && c.pages[utils.NormalizeURLPath(target)] {
+		return nil
+	}
+	if !c.Remote {
+		if !local {
+			// External links are only checked in remote mode
+			return nil
+		}
+		return &Problem{Source: source, Line: line, Link: link, Reason: "no processed page for " + target}
+	}
+
+	remoteURL := target
+	if local {
+		if c.BaseURL == "" {
+			return &Problem{Source: source, Line: line, Link: link, Reason: "no processed page for " + target}
+		}
+		remoteURL = strings.TrimSuffix(c.BaseURL, "/") + target
+	}
+	if err := c.fetch(remoteURL); err != nil {
+		return &Problem{Source: source, Line: line, Link: link, Reason: err.Error()}
+	}
+	return nil
+}
+
+// markdownLink matches inline Markdown links and images: [text](target "title")
+var markdownLink = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+// htmlLink matches href attributes left in the body
+var htmlLink = regexp.MustCompile(`href\s*=\s*["']([^"']+)["']`)
+
+// CheckBody scans the extracted body of a page for links and verifies each one.
+// Line numbers are relative to the body text.
+func (c *Checker) CheckBody(source, pageURL, body string) []Problem {
+	var problems []Problem
+	for i, line := range strings.Split(body, "\n") {
+		var links []string
+		for _, m := range markdownLink.FindAllStringSubmatch(line, -1) {
+			links = append(links, m[1])
+		}
+		for _, m := range htmlLink.FindAllStringSubmatch(line, -1) {
+			links = append(links, m[1])
+		}
+		for _, link := range links {
+			if p := c.CheckLink(source, i+1, pageURL, link); p != nil {
+				problems = append(problems, *p)
+			}
+		}
+	}
+	return problems
+}
+
+// FindLine returns the 1-based line of the first occurrence of needle in text, or 0
+func FindLine(text, needle string) int {
+	idx := strings.Index(text, needle)
+	if idx < 0 {
+		return 0
+	}
+	return strings.Count(text[:idx], "\n") + 1
+}
+
+// FindLinkLine returns the 1-based line of the first href in an HTML source
+// that resolves to the same target as link, or 0. Links in extracted bodies
+// are resolved against pageURL, so they rarely appear in the source as written.
+func (c *Checker) FindLinkLine(source, pageURL, link string) int {
+	want, wantLocal, err := c.resolve(pageURL, link)
+	if err != nil || want == "" {
+		return FindLine(source, link)
+	}
+	if wantLocal {
+		want = utils.NormalizeURLPath(want)
+	}
+	for i, line := range strings.Split(source, "\n") {
+		for _, m := range htmlLink.FindAllStringSubmatch(line, -1) {
+			target, local, err := c.resolve(pageURL, m[1])
+			if err != nil || local != wantLocal {
+				continue
+			}
+			if local {
+				target = utils.NormalizeURLPath(target)
+			}
+			if target == want {
+				return i + 1
+			}
+		}
+	}
+	return FindLine(source, link)
+}
+
+// resolve turns a link into either a site-local path or an absolute external URL.
+// An empty target means the link should not be checked.
+func (c *Checker) resolve(pageURL, link string) (target string, local bool, err error) {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") {
+		return "", false, nil
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid link: %w", err)
+	}
+
+	switch u.Scheme {
+	case "":
+		// Relative or root-relative link
+	case "http", "https":
+		base, _ := url.Parse(c.BaseURL)
+		if base == nil || base.Host == "" || !strings.EqualFold(base.Host, u.Host) {
+			u.Fragment = ""
+			return u.String(), false, nil
+		}
+		// Same host as the base URL: check it as a local page
+		p := strings.TrimPrefix(u.Path, strings.TrimSuffix(base.Path, "/"))
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		return p, true, nil
+	default:
+		// mailto:, tel:, javascript: and similar
+		return "", false, nil
+	}
+
+	if u.Path == "" {
+		// Query-only or fragment-only link to the same page
+		return "", false, nil
+	}
+	if strings.HasPrefix(u.Path, "/") {
+		return u.Path, true, nil
+	}
+	dir := path.Dir(pageURL)
+	if strings.HasSuffix(pageURL, "/") {
+		dir = pageURL
+	}
+	return path.Join("/", dir, u.Path), true, nil
+}
+
+// fetch requests url and reports an error unless it answers 200 OK
+func (c *Checker) fetch(u string) error {
+	if err, ok := c.remote[u]; ok {
+		return err
+	}
+	var err error
+	resp, reqErr := c.Client.Get(u)
+	if reqErr != nil {
+		err = fmt.Errorf("request failed: %w", reqErr)
+	} else {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("%s returned %s", u, resp.Status)
+		}
+	}
+	c.remote[u] = err
+	return err
+}
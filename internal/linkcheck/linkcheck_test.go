@@ -0,0 +1,114 @@
+package linkcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckLink(t *testing.T) {
+	checker := New("https://docs.example.com", false)
+	checker.AddPage("/guide/setup")
+	checker.AddPage("/guide")
+
+	tests := []struct {
+		name    string
+		pageURL string
+		link    string
+		broken  bool
+	}{
+		{name: "root-relative", pageURL: "/guide/intro", link: "/guide/setup", broken: false},
+		{name: "relative with extension", pageURL: "/guide/intro", link: "setup.html", broken: false},
+		{name: "parent directory", pageURL: "/api/ref", link: "../guide/setup.html#linux", broken: false},
+		{name: "directory index", pageURL: "/api/ref", link: "/guide/index.html", broken: false},
+		{name: "same host absolute", pageURL: "/api/ref", link: "https://docs.example.com/guide/setup", broken: false},
+		{name: "missing page", pageURL: "/guide/intro", link: "/guide/missing", broken: true},
+		{name: "external link", pageURL: "/guide/intro", link: "https://golang.org/", broken: false},
+		{name: "anchor", pageURL: "/guide/intro", link: "#usage", broken: false},
+		{name: "mailto", pageURL: "/guide/intro", link: "mailto:docs@example.com", broken: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := checker.CheckLink("page.html", 1, tt.pageURL, tt.link)
+			if (p != nil) != tt.broken {
+				t.Errorf("CheckLink(%q) = %v, want broken=%v", tt.link, p, tt.broken)
+			}
+		})
+	}
+}
+
+func TestCheckLinkRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/live" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	checker := New(server.URL, true)
+	if p := checker.CheckLink("page.html", 1, "/", "/live"); p != nil {
+		t.Errorf("Expected /live to resolve remotely, got %v", p)
+	}
+	p := checker.CheckLink("page.html", 1, "/", "/gone")
+	if p == nil {
+		t.Fatalf("Expected /gone to be reported as broken")
+	}
+	if !strings.Contains(p.Reason, "404") {
+		t.Errorf("Expected 404 in reason, got %q", p.Reason)
+	}
+}
+
+func TestCheckBody(t *testing.T) {
+	checker := New("", false)
+	checker.AddPage("/section1/page1")
+
+	body := "Intro paragraph.\nSee [page one](/section1/page1) and [a missing page](../section1/nope).\n<a href=\"/gone\">gone</a>"
+	problems := checker.CheckBody("section2/page2.html", "/section2/page2", body)
+
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %d: %v", len(problems), problems)
+	}
+	if problems[0].Line != 2 || problems[0].Link != "../section1/nope" {
+		t.Errorf("Unexpected first problem: %v", problems[0])
+	}
+	if problems[1].Line != 3 || problems[1].Link != "/gone" {
+		t.Errorf("Unexpected second problem: %v", problems[1])
+	}
+	if got := problems[0].String(); got != "section2/page2.html:2: ../section1/nope: no processed page for /section1/nope" {
+		t.Errorf("Unexpected problem string: %s", got)
+	}
+}
+
+func TestFindLine(t *testing.T) {
+	text := "<urlset>\n  <url>\n    <loc>http://example.com/a</loc>\n"
+	if got := FindLine(text, "http://example.com/a"); got != 3 {
+		t.Errorf("FindLine() = %d, want 3", got)
+	}
+	if got := FindLine(text, "http://example.com/b"); got != 0 {
+		t.Errorf("FindLine() = %d, want 0", got)
+	}
+}
+
+func TestFindLinkLine(t *testing.T) {
+	checker := New("https://docs.example.com/docs", false)
+	source := "<html>\n<a href=\"setup.html\">Setup</a>\n<a href='../api/ref.html#get'>Ref</a>\n<a href=\"https://docs.example.com/docs/guide/faq\">FAQ</a>\n</html>"
+
+	tests := []struct {
+		link string
+		want int
+	}{
+		{link: "/guide/setup", want: 2},
+		{link: "/api/ref.html#get", want: 3},
+		{link: "/guide/faq.md", want: 4},
+		{link: "/guide/missing", want: 0},
+	}
+	for _, tt := range tests {
+		if got := checker.FindLinkLine(source, "/guide/intro", tt.link); got != tt.want {
+			t.Errorf("FindLinkLine(%q) = %d, want %d", tt.link, got, tt.want)
+		}
+	}
+}
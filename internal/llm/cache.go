@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores replies by a hash of the model and prompt, so unchanged pages
+// are not sent to the model again. A Cache with an empty path is kept in memory.
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]string
+	dirty   bool
+}
+
+// LoadCache reads the cache file at path; a missing file yields an empty cache
+func LoadCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]string)}
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("error parsing cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Get returns the cached reply for key
+func (c *Cache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+// Put stores a reply
+func (c *Cache) Put(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] != value {
+		c.entries[key] = value
+		c.dirty = true
+	}
+}
+
+// Save writes the cache file if it changed
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("error writing cache %s: %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// cacheKey hashes the parts of a request
+func cacheKey(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,93 @@
+// Package llm writes page notes and site summaries with an OpenAI-compatible
+// chat completion endpoint, such as a local llama.cpp or Ollama server
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client calls the chat completion API of an OpenAI-compatible server
+type Client struct {
+	Endpoint   string       // Base URL of the API, e.g. "http://localhost:11434/v1"
+	Model      string       // Model name passed to the server
+	APIKey     string       // Bearer token (optional for local servers)
+	HTTPClient *http.Client // HTTP client used for requests
+}
+
+// NewClient creates a Client for the given endpoint and model
+func NewClient(endpoint, model, apiKey string) *Client {
+	return &Client{
+		Endpoint:   endpoint,
+		Model:      model,
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Complete sends a system and a user message and returns the reply of the model
+func (c *Client) Complete(ctx context.Context, system, user string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: c.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		Temperature: 0, // Keep replies as deterministic as the server allows
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding request: %w", err)
+	}
+
+	url := strings.TrimSuffix(c.Endpoint, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var reply chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return "", fmt.Errorf("error decoding response from %s: %w", url, err)
+	}
+	if len(reply.Choices) == 0 {
+		return "", fmt.Errorf("%s returned no choices", url)
+	}
+	return reply.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,200 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeServer answers chat completions with the title of the page
+type fakeServer struct {
+	requests atomic.Int32
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests.Add(1)
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.peak {
+		f.peak = f.inFlight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer secret" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "tiny" || len(req.Messages) != 2 {
+		http.Error(w, "bad request body", http.StatusBadRequest)
+		return
+	}
+	title, _, _ := strings.Cut(strings.TrimPrefix(req.Messages[1].Content, "Title: "), "\n")
+	var reply chatResponse
+	reply.Choices = append(reply.Choices, struct {
+		Message chatMessage `json:"message"`
+	}{chatMessage{Role: "assistant", Content: "\"Explains " + title + ".\n\n\""}})
+	json.NewEncoder(w).Encode(reply)
+}
+
+func TestPageNotes(t *testing.T) {
+	fake := &fakeServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := LoadCache(cachePath)
+	if err != nil {
+		t.Fatalf("LoadCache() error = %v", err)
+	}
+	s := NewSummarizer(NewClient(server.URL+"/v1/", "tiny", "secret"), cache, 2)
+
+	pages := []Page{
+		{Title: "Install", Body: "Run the installer."},
+		{Title: "Configure", Body: "Edit the file."},
+		{Title: "Deploy", Body: "Push the build."},
+		{Title: "Upgrade", Body: "Read the notes."},
+	}
+	notes := s.PageNotes(context.Background(), pages)
+	want := []string{"Explains Install.", "Explains Configure.", "Explains Deploy.", "Explains Upgrade."}
+	for i := range want {
+		if notes[i] != want[i] {
+			t.Errorf("notes[%d] = %q, want %q", i, notes[i], want[i])
+		}
+	}
+	if s.Err() != nil {
+		t.Errorf("Err() = %v", s.Err())
+	}
+	if fake.peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", fake.peak)
+	}
+
+	// Replies are cached by content
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := LoadCache(cachePath)
+	if err != nil {
+		t.Fatalf("LoadCache() error = %v", err)
+	}
+	s = NewSummarizer(NewClient(server.URL+"/v1", "tiny", "secret"), reloaded, 2)
+	pages[3].Body = "Read the release notes."
+	s.PageNotes(context.Background(), pages)
+	if got := fake.requests.Load(); got != 5 {
+		t.Errorf("requests = %d, want 5 (one per changed page)", got)
+	}
+}
+
+func TestFallbackWhenDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := server.URL
+	server.Close()
+
+	cache, _ := LoadCache("")
+	s := NewSummarizer(NewClient(endpoint, "tiny", ""), cache, 1)
+	s.RetryDelay = time.Millisecond
+	notes := s.PageNotes(context.Background(), []Page{
+		{Title: "A", Fallback: "Notes of A."},
+		{Title: "B", Fallback: "Notes of B."},
+	})
+	if notes[0] != "Notes of A." || notes[1] != "Notes of B." {
+		t.Errorf("notes = %q, want the fallbacks", notes)
+	}
+	if s.Err() == nil {
+		t.Error("Err() = nil, want the connection error")
+	}
+	if got := s.SiteSummary(context.Background(), "Docs", nil, "Default summary."); got != "Default summary." {
+		t.Errorf("SiteSummary() = %q, want the fallback", got)
+	}
+	if got := s.Fallbacks(); got != 3 {
+		t.Errorf("Fallbacks() = %d, want 3", got)
+	}
+}
+
+// flakyServer fails requests for pages titled "Broken" and the first
+// failFirst requests, and answers the others like fakeServer
+type flakyServer struct {
+	fakeServer
+	failFirst int32
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if f.requests.Load() < f.failFirst || bytes.Contains(body, []byte("Title: Broken")) {
+		f.requests.Add(1)
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		return
+	}
+	f.fakeServer.ServeHTTP(w, r)
+}
+
+func TestFallbackPerRequest(t *testing.T) {
+	flaky := &flakyServer{failFirst: 1}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	cache, _ := LoadCache("")
+	s := NewSummarizer(NewClient(server.URL+"/v1", "tiny", "secret"), cache, 1)
+	s.RetryDelay = time.Millisecond
+	notes := s.PageNotes(context.Background(), []Page{
+		{Title: "Install", Fallback: "Notes of Install."},
+		{Title: "Broken", Fallback: "Notes of Broken."},
+		{Title: "Usage", Fallback: "Notes of Usage."},
+	})
+
+	// The first request is retried, the broken page falls back alone
+	want := []string{"Explains Install.", "Notes of Broken.", "Explains Usage."}
+	for i := range want {
+		if notes[i] != want[i] {
+			t.Errorf("notes[%d] = %q, want %q", i, notes[i], want[i])
+		}
+	}
+	if got := s.Fallbacks(); got != 1 {
+		t.Errorf("Fallbacks() = %d, want 1", got)
+	}
+	if s.Err() == nil {
+		t.Error("Err() = nil, want the error of the broken page")
+	}
+}
+
+func TestStopAfterMaxFailures(t *testing.T) {
+	flaky := &flakyServer{failFirst: 1000}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	cache, _ := LoadCache("")
+	s := NewSummarizer(NewClient(server.URL+"/v1", "tiny", "secret"), cache, 1)
+	s.Retries = 0
+	s.MaxFailures = 2
+	pages := make([]Page, 5)
+	for i := range pages {
+		pages[i] = Page{Title: "Page", Body: strings.Repeat("x", i), Fallback: "Fallback."}
+	}
+	s.PageNotes(context.Background(), pages)
+
+	if got := flaky.requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2 before giving up", got)
+	}
+	if got := s.Fallbacks(); got != 5 {
+		t.Errorf("Fallbacks() = %d, want 5", got)
+	}
+}
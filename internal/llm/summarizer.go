@@ -0,0 +1,190 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// DefaultConcurrency is the default number of simultaneous requests
+const DefaultConcurrency = 4
+
+// DefaultMaxFailures is the default number of consecutive failed requests
+// after which the endpoint is no longer called
+const DefaultMaxFailures = 3
+
+// Retries of a failed request made by summarizers created with NewSummarizer
+const (
+	defaultRetries    = 1
+	defaultRetryDelay = time.Second
+)
+
+// maxInputRunes caps the page text sent to the model
+const maxInputRunes = 12000
+
+const pagePrompt = "You write the notes of an llms.txt index. Reply with one plain sentence, " +
+	"without Markdown or a leading label, describing what the documentation page covers and when to read it."
+
+const sitePrompt = "You write the summary of an llms.txt index. Reply with one or two plain sentences, " +
+	"without Markdown, describing what the documented project is and what the documentation covers."
+
+// Page is a page to describe
+type Page struct {
+	Title    string
+	Body     string
+	Fallback string // Notes used when the endpoint fails
+}
+
+// Summarizer describes pages and sites, caching replies and falling back to
+// deterministic text for each request that fails
+type Summarizer struct {
+	Client      *Client
+	Cache       *Cache
+	Concurrency int           // Maximum simultaneous requests (0 = DefaultConcurrency)
+	MaxFailures int           // Consecutive failed requests after which all calls fall back (0 = DefaultMaxFailures)
+	Retries     int           // Additional attempts of a failed request
+	RetryDelay  time.Duration // Delay before the first retry, doubled for each further one
+
+	failures  atomic.Int32 // Consecutive failed requests
+	fallbacks atomic.Int32 // Replies replaced by their fallback
+	errMu     sync.Mutex
+	err       error
+}
+
+// NewSummarizer creates a Summarizer that retries a failed request once
+func NewSummarizer(client *Client, cache *Cache, concurrency int) *Summarizer {
+	return &Summarizer{
+		Client:      client,
+		Cache:       cache,
+		Concurrency: concurrency,
+		Retries:     defaultRetries,
+		RetryDelay:  defaultRetryDelay,
+	}
+}
+
+// Err returns the error of the last failed request, if any
+func (s *Summarizer) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// Fallbacks returns the number of replies replaced by their fallback text
+func (s *Summarizer) Fallbacks() int {
+	return int(s.fallbacks.Load())
+}
+
+// PageNotes returns a one-line description of each page, in order, using at
+// most Concurrency simultaneous requests. Pages whose request fails get their
+// Fallback notes.
+func (s *Summarizer) PageNotes(ctx context.Context, pages []Page) []string {
+	notes := make([]string, len(pages))
+	limit := s.Concurrency
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, page := range pages {
+		wg.Add(1)
+		go func(i int, page Page) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			input := fmt.Sprintf("Title: %s\n\n%s", page.Title, truncateInput(page.Body))
+			notes[i] = s.complete(ctx, pagePrompt, input, page.Fallback)
+		}(i, page)
+	}
+	wg.Wait()
+	return notes
+}
+
+// SiteSummary returns a short summary of a project from the titles and notes
+// of its pages, or fallback when the request fails
+func (s *Summarizer) SiteSummary(ctx context.Context, projectName string, pages []Page, fallback string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Project: %s\n\nPages:\n", projectName)
+	for _, page := range pages {
+		fmt.Fprintf(&sb, "- %s: %s\n", page.Title, page.Fallback)
+	}
+	return s.complete(ctx, sitePrompt, truncateInput(sb.String()), fallback)
+}
+
+// complete returns the cached or generated reply to a prompt, flattened to one line
+func (s *Summarizer) complete(ctx context.Context, system, input, fallback string) string {
+	key := cacheKey(s.Client.Model, system, input)
+	if s.Cache != nil {
+		if reply, ok := s.Cache.Get(key); ok {
+			return reply
+		}
+	}
+	maxFailures := s.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	if int(s.failures.Load()) >= maxFailures {
+		// The endpoint looks down; skip the request
+		s.fallbacks.Add(1)
+		return fallback
+	}
+
+	reply, err := s.request(ctx, system, input)
+	if err != nil {
+		s.failures.Add(1)
+		s.fallbacks.Add(1)
+		s.errMu.Lock()
+		s.err = err
+		s.errMu.Unlock()
+		return fallback
+	}
+	s.failures.Store(0)
+	if s.Cache != nil {
+		s.Cache.Put(key, reply)
+	}
+	return reply
+}
+
+// request sends a prompt, retrying after a growing delay when it fails
+func (s *Summarizer) request(ctx context.Context, system, input string) (string, error) {
+	delay := s.RetryDelay
+	for attempt := 0; ; attempt++ {
+		reply, err := s.Client.Complete(ctx, system, input)
+		if err == nil {
+			reply = oneLine(reply)
+			if reply == "" {
+				err = fmt.Errorf("empty reply from %s", s.Client.Endpoint)
+			}
+		}
+		if err == nil || attempt >= s.Retries {
+			return reply, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// oneLine joins the lines of a reply and strips quotes models like to add
+func oneLine(reply string) string {
+	reply = utils.NormalizeSpace(reply)
+	return strings.Trim(reply, "\"'“”「」 ")
+}
+
+// truncateInput shortens text sent to the model
+func truncateInput(text string) string {
+	if utf8.RuneCountInString(text) <= maxInputRunes {
+		return text
+	}
+	return string([]rune(text)[:maxInputRunes])
+}
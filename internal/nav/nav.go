@@ -0,0 +1,178 @@
+// Package nav reads the navigation tree of a site from its rendered sidebar
+package nav
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/timakin/llmstxt-gen/internal/htmlutil"
+)
+
+// Item is an entry of the navigation tree
+type Item struct {
+	Title    string  // Link or group label
+	Href     string  // Link target as written, empty for groups without a page
+	Children []*Item // Nested entries
+}
+
+// Parse reads an HTML page and returns the navigation tree of the first
+// element matching selector
+func Parse(r io.Reader, selector string) ([]*Item, error) {
+	sel, err := htmlutil.Compile(selector)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+	root := sel.Query(doc)
+	if root == nil {
+		return nil, fmt.Errorf("no element matches %q", selector)
+	}
+
+	if list := firstList(root); list != nil {
+		return parseList(list), nil
+	}
+
+	// Navigation without list markup: treat every link as a top-level entry
+	var items []*Item
+	for _, a := range htmlutil.FindAll(root, htmlutil.ByTag(atom.A)) {
+		if title := htmlutil.Text(a); title != "" {
+			items = append(items, &Item{Title: title, Href: htmlutil.Attr(a, "href")})
+		}
+	}
+	return items, nil
+}
+
+// Resolve turns an href found on the page at pageURL into a root-relative
+// path below basePath, the path prefix of the published site, which is
+// stripped. It returns an empty string for anchors, links to other hosts and
+// links outside basePath.
+func Resolve(basePath, pageURL, href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || u.Path == "" || u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		base = &url.URL{Path: pageURL}
+	}
+	if u.Host != "" && (base.Host == "" || !strings.EqualFold(base.Host, u.Host)) {
+		return ""
+	}
+
+	target := u.Path
+	if !strings.HasPrefix(target, "/") {
+		dir := path.Dir(base.Path)
+		if strings.HasSuffix(base.Path, "/") {
+			dir = base.Path
+		}
+		target = path.Join("/", dir, target)
+	}
+
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath == "" {
+		return target
+	}
+	if target != basePath && !strings.HasPrefix(target, basePath+"/") {
+		return ""
+	}
+	if target = strings.TrimPrefix(target, basePath); target == "" {
+		return "/"
+	}
+	return target
+}
+
+// Walk calls fn for every item in preorder with the chain of its ancestors
+func Walk(items []*Item, fn func(item *Item, ancestors []*Item)) {
+	var walk func([]*Item, []*Item)
+	walk = func(items []*Item, ancestors []*Item) {
+		for _, item := range items {
+			fn(item, ancestors)
+			walk(item.Children, append(ancestors[:len(ancestors):len(ancestors)], item))
+		}
+	}
+	walk(items, nil)
+}
+
+// parseList converts a <ul>/<ol> into items, one per direct <li>
+func parseList(list *html.Node) []*Item {
+	var items []*Item
+	for li := list.FirstChild; li != nil; li = li.NextSibling {
+		if !htmlutil.IsElement(li, atom.Li) {
+			continue
+		}
+		item := &Item{}
+		if a := findOutsideLists(li, htmlutil.ByTag(atom.A)); a != nil {
+			item.Title = htmlutil.Text(a)
+			item.Href = htmlutil.Attr(a, "href")
+		}
+		if item.Title == "" {
+			item.Title = labelText(li)
+		}
+		if sub := firstList(li); sub != nil {
+			item.Children = parseList(sub)
+		}
+		if item.Title != "" || len(item.Children) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// firstList returns the first <ul> or <ol> below n
+func firstList(n *html.Node) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := htmlutil.Find(c, isList); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findOutsideLists returns the first descendant of n matching match that is
+// not inside a nested list
+func findOutsideLists(n *html.Node, match func(*html.Node) bool) *html.Node {
+	var found *html.Node
+	for c := n.FirstChild; c != nil && found == nil; c = c.NextSibling {
+		htmlutil.Walk(c, func(d *html.Node) bool {
+			if found != nil || isList(d) {
+				return false
+			}
+			if d.Type == html.ElementNode && match(d) {
+				found = d
+				return false
+			}
+			return true
+		})
+	}
+	return found
+}
+
+// labelText returns the text of an item without its nested lists
+func labelText(li *html.Node) string {
+	var parts []string
+	for c := li.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (isList(c) || htmlutil.Find(c, isList) != nil) {
+			if label := findOutsideLists(c, func(d *html.Node) bool {
+				return d.DataAtom == atom.Summary || d.DataAtom == atom.Button || d.DataAtom == atom.Span
+			}); label != nil {
+				parts = append(parts, htmlutil.Text(label))
+			}
+			continue
+		}
+		parts = append(parts, htmlutil.Text(c))
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+func isList(n *html.Node) bool {
+	return htmlutil.IsElement(n, atom.Ul) || htmlutil.IsElement(n, atom.Ol)
+}
@@ -0,0 +1,91 @@
+package nav
+
+import (
+	"strings"
+	"testing"
+)
+
+const sidebar = `<html><body>
+<nav class="sidebar">
+  <ul>
+    <li><a href="/intro.html">Introduction</a></li>
+    <li>
+      <details open><summary>Guides</summary>
+        <ul>
+          <li><a href="guides/install.html">Install</a></li>
+          <li class="category">
+            <div><a href="/guides/advanced/">Advanced</a></div>
+            <ul>
+              <li><a href="/guides/advanced/tuning.html">Tuning</a></li>
+            </ul>
+          </li>
+        </ul>
+      </details>
+    </li>
+    <li><a href="https://github.com/example/repo">GitHub</a></li>
+  </ul>
+</nav>
+<main><ul><li><a href="/other">Not navigation</a></li></ul></main>
+</body></html>`
+
+func TestParse(t *testing.T) {
+	items, err := Parse(strings.NewReader(sidebar), "nav.sidebar")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var got []string
+	Walk(items, func(item *Item, ancestors []*Item) {
+		var titles []string
+		for _, a := range ancestors {
+			titles = append(titles, a.Title)
+		}
+		got = append(got, strings.Join(append(titles, item.Title), " > ")+" = "+item.Href)
+	})
+
+	want := []string{
+		"Introduction = /intro.html",
+		"Guides = ",
+		"Guides > Install = guides/install.html",
+		"Guides > Advanced = /guides/advanced/",
+		"Guides > Advanced > Tuning = /guides/advanced/tuning.html",
+		"GitHub = https://github.com/example/repo",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Parse() tree =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParseNoMatch(t *testing.T) {
+	if _, err := Parse(strings.NewReader(sidebar), "aside.menu"); err == nil {
+		t.Errorf("Expected an error when the selector does not match")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		basePath string
+		pageURL  string
+		href     string
+		want     string
+	}{
+		{"", "/index", "guides/install.html", "/guides/install.html"},
+		{"", "/docs/", "setup", "/docs/setup"},
+		{"", "/docs/intro", "../api/", "/api"},
+		{"", "/index", "/guides/", "/guides/"},
+		{"", "/index", "https://github.com/example/repo", ""},
+		{"", "/index", "https://docs.example.com/guides/", ""},
+		{"", "https://docs.example.com/", "https://docs.example.com/guides/", "/guides/"},
+		{"", "/index", "#top", ""},
+		{"/docs", "https://example.com/docs/guide/intro", "https://example.com/docs/api/", "/api/"},
+		{"/docs/", "https://example.com/docs/guide/intro", "/docs/guide/setup", "/guide/setup"},
+		{"/docs", "https://example.com/docs/guide/intro", "setup", "/guide/setup"},
+		{"/docs", "https://example.com/docs/guide/intro", "../", "/"},
+		{"/docs", "https://example.com/docs/guide/intro", "/blog/post", ""},
+	}
+	for _, tt := range tests {
+		if got := Resolve(tt.basePath, tt.pageURL, tt.href); got != tt.want {
+			t.Errorf("Resolve(%q, %q, %q) = %q, want %q", tt.basePath, tt.pageURL, tt.href, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package summarize produces the short notes listed next to each page link
+// from the Markdown body of the page
+package summarize
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+// Strategies for the notes of a page
+const (
+	StrategyMeta           = "meta"            // Meta description, falling back to the first paragraph
+	StrategyFirstParagraph = "first-paragraph" // Leading prose of the body
+	StrategyTextRank       = "textrank"        // Most central sentences of the body
+)
+
+var (
+	// Blocks that are not prose: headings, list items, quotes, fences, tables,
+	// images and thematic breaks. Emphasis such as "*Note*" is prose.
+	blockMarker    = regexp.MustCompile("^(?:#{1,6}(?:\\s|$)|[-*+](?:\\s|$)|>|```|~~~|\\||!\\[|\\d+[.)](?:\\s|$)|(?:[-*_][ \\t]*){3,}$)")
+	markdownImage  = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownAnchor = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownMarker = regexp.MustCompile("\\*\\*|__|`|^#+ ")
+)
+
+// Paragraphs returns the prose paragraphs of a Markdown body as plain text,
+// skipping headings, code, tables, quotes, lists and images
+func Paragraphs(body string) []string {
+	var paragraphs []string
+	for _, block := range strings.Split(body, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" || blockMarker.MatchString(block) {
+			continue
+		}
+		if text := PlainText(block); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	return paragraphs
+}
+
+// PlainText strips inline Markdown syntax from text, keeping link texts
+func PlainText(text string) string {
+	text = markdownImage.ReplaceAllString(text, "")
+	text = markdownAnchor.ReplaceAllString(text, "$1")
+	return strings.TrimSpace(markdownMarker.ReplaceAllString(text, ""))
+}
+
+// FirstParagraph returns the first prose paragraph of a Markdown body, cut to
+// at most maxRunes characters on a sentence or word boundary. Bodies without
+// prose paragraphs use their whole text.
+func FirstParagraph(body string, maxRunes int) string {
+	if paragraphs := Paragraphs(body); len(paragraphs) > 0 {
+		return utils.Truncate(paragraphs[0], maxRunes)
+	}
+	return utils.Truncate(PlainText(body), maxRunes)
+}
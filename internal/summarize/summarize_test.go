@@ -0,0 +1,57 @@
+package summarize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFirstParagraph(t *testing.T) {
+	body := "## Overview\n\n```sh\nmake\n```\n\nThe **CLI** builds an [index](/guide/index) of your docs. It runs offline.\n\n- Fast\n- Simple"
+	if got, want := FirstParagraph(body, 60), "The CLI builds an index of your docs. It runs offline."; got != want {
+		t.Errorf("FirstParagraph() = %q, want %q", got, want)
+	}
+	if got, want := FirstParagraph("- Only\n- A list", 60), "- Only - A list"; got != want {
+		t.Errorf("FirstParagraph() without prose = %q, want %q", got, want)
+	}
+	// Prose after the first paragraph, or below a later heading, is left out
+	if got, want := FirstParagraph("Short intro.\n\n## Install\n\nRun make.", 200), "Short intro."; got != want {
+		t.Errorf("FirstParagraph() across headings = %q, want %q", got, want)
+	}
+}
+
+func TestParagraphs(t *testing.T) {
+	body := "# Title\n\n*Note*: emphasis is prose.\n\n- item\n\n* item\n\n+ item\n\n1. step\n\n> quote\n\n" +
+		"```go\ncode\n```\n\n~~~\ncode\n~~~\n\n| a | b |\n\n![logo](/logo.png)\n\n---\n\n" +
+		"!important flags are described below.\n\n+1 for this option.\n\n-v prints more logs.\n\n#hashtag support"
+	got := Paragraphs(body)
+	want := []string{"*Note*: emphasis is prose.", "!important flags are described below.", "+1 for this option.", "-v prints more logs.", "#hashtag support"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Paragraphs() = %q, want %q", got, want)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	got := Sentences("Install it. Version 1.2 is required!\nSee the guide 設定します。次に実行します")
+	want := []string{"Install it.", "Version 1.2 is required!", "See the guide 設定します。", "次に実行します"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sentences() = %q, want %q", got, want)
+	}
+}
+
+func TestTextRank(t *testing.T) {
+	body := "Welcome to our documentation.\n\n" +
+		"The generator reads HTML pages and writes an llms.txt index of the pages. " +
+		"Thanks for reading. " +
+		"Each index entry links a page and adds notes generated from the HTML pages. " +
+		"The llms.txt index helps language models find the right pages."
+
+	got := TextRank(body, 150)
+	want := "The generator reads HTML pages and writes an llms.txt index of the pages. " +
+		"The llms.txt index helps language models find the right pages."
+	if got != want {
+		t.Errorf("TextRank() = %q, want %q", got, want)
+	}
+	if got := TextRank("```go\nfunc main() {}\n```", 100); got != "" {
+		t.Errorf("TextRank() without prose = %q, want empty", got)
+	}
+}
@@ -0,0 +1,188 @@
+package summarize
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/timakin/llmstxt-gen/pkg/utils"
+)
+
+const (
+	damping    = 0.85 // TextRank damping factor
+	iterations = 50   // Maximum number of rank updates
+	tolerance  = 1e-6 // Rank change below which iteration stops
+)
+
+// stopWords are common English words ignored when comparing sentences
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "can": true, "for": true, "from": true, "how": true, "in": true, "is": true,
+	"it": true, "its": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "when": true, "which": true, "will": true,
+	"with": true, "you": true, "your": true,
+}
+
+// TextRank returns the most central sentences of the prose of a Markdown
+// body, in document order, fitting in maxRunes characters. Sentences are
+// ranked with TextRank over a graph weighted by word overlap. It returns ""
+// when the body has no prose.
+func TextRank(body string, maxRunes int) string {
+	var sentences []string
+	for _, p := range Paragraphs(body) {
+		sentences = append(sentences, Sentences(p)...)
+	}
+	if len(sentences) == 0 {
+		return ""
+	}
+
+	ranks := rankSentences(sentences)
+	byRank := make([]int, len(sentences))
+	for i := range byRank {
+		byRank[i] = i
+	}
+	sort.SliceStable(byRank, func(a, b int) bool { return ranks[byRank[a]] > ranks[byRank[b]] })
+
+	selected := make([]bool, len(sentences))
+	length := 0
+	for n, i := range byRank {
+		l := utf8.RuneCountInString(sentences[i])
+		if n > 0 && length+l > maxRunes {
+			continue
+		}
+		selected[i] = true
+		length += l
+	}
+
+	var summary []string
+	for i, s := range sentences {
+		if selected[i] {
+			summary = append(summary, s)
+		}
+	}
+	return utils.Truncate(strings.Join(summary, "\n"), maxRunes)
+}
+
+// rankSentences computes the TextRank score of each sentence
+func rankSentences(sentences []string) []float64 {
+	n := len(sentences)
+	terms := make([]map[string]bool, n)
+	for i, s := range sentences {
+		terms[i] = sentenceTerms(s)
+	}
+
+	weights := make([][]float64, n)
+	totals := make([]float64, n)
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			w := similarity(terms[i], terms[j])
+			weights[i][j], weights[j][i] = w, w
+			totals[i] += w
+			totals[j] += w
+		}
+	}
+
+	ranks := make([]float64, n)
+	for i := range ranks {
+		ranks[i] = 1
+	}
+	for iter := 0; iter < iterations; iter++ {
+		next := make([]float64, n)
+		delta := 0.0
+		for i := 0; i < n; i++ {
+			sum := 0.0
+			for j := 0; j < n; j++ {
+				if weights[j][i] > 0 {
+					sum += weights[j][i] / totals[j] * ranks[j]
+				}
+			}
+			next[i] = 1 - damping + damping*sum
+			delta += math.Abs(next[i] - ranks[i])
+		}
+		ranks = next
+		if delta < tolerance {
+			break
+		}
+	}
+	return ranks
+}
+
+// similarity is the TextRank sentence similarity: shared terms normalized by
+// the logarithm of the sentence lengths
+func similarity(a, b map[string]bool) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	if shared == 0 {
+		return 0
+	}
+	return float64(shared) / (math.Log(float64(len(a))) + math.Log(float64(len(b))))
+}
+
+// sentenceTerms returns the distinct terms of a sentence: lower-cased words
+// except stop words, and pairs of adjacent characters in CJK text
+func sentenceTerms(sentence string) map[string]bool {
+	terms := make(map[string]bool)
+	var word strings.Builder
+	var prev rune
+	flush := func() {
+		if w := word.String(); w != "" && !stopWords[w] {
+			terms[w] = true
+		}
+		word.Reset()
+	}
+	for _, r := range strings.ToLower(sentence) {
+		switch {
+		case utils.IsCJK(r):
+			flush()
+			if utils.IsCJK(prev) {
+				terms[string([]rune{prev, r})] = true
+			}
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+		prev = r
+	}
+	flush()
+	return terms
+}
+
+// Sentences splits plain text into sentences, ending at ".", "!" or "?"
+// followed by a space and at CJK sentence terminators
+func Sentences(text string) []string {
+	var sentences []string
+	runes := []rune(utils.NormalizeSpace(text))
+	start := 0
+	for i, r := range runes {
+		end := false
+		switch r {
+		case '。', '！', '？':
+			end = true
+		case '.', '!', '?':
+			end = i+1 == len(runes) || runes[i+1] == ' '
+		}
+		if end {
+			if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
@@ -0,0 +1,43 @@
+package utils
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"changelog/**", "changelog/v1.html", true},
+		{"changelog/**", "changelog/2024/v1.html", true},
+		{"changelog/**", "guide/changelog.html", false},
+		{"blog/*.html", "blog/post.html", true},
+		{"blog/*.html", "blog/2024/post.html", false},
+		{"**/draft-*.html", "guide/advanced/draft-x.html", true},
+		{"**/draft-*.html", "draft-x.html", true},
+		{"guide", "guide", true},
+		{"/guide/**", "guide/setup.html", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := SplitList(" a, ,b ,c")
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("SplitList() = %q", got)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcdefgh"); got != 2 {
+		t.Errorf("EstimateTokens(latin) = %d, want 2", got)
+	}
+	if got := EstimateTokens("日本語"); got != 3 {
+		t.Errorf("EstimateTokens(cjk) = %d, want 3", got)
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ellipsis is appended to text shortened within a sentence
+const Ellipsis = "..."
+
+// NormalizeSpace collapses runs of whitespace into single spaces. Line breaks
+// between CJK characters are removed instead, since CJK text has no spaces.
+func NormalizeSpace(text string) string {
+	var sb strings.Builder
+	for _, field := range strings.Fields(text) {
+		if sb.Len() > 0 {
+			last, _ := utf8.DecodeLastRuneInString(sb.String())
+			first, _ := utf8.DecodeRuneInString(field)
+			if !(isCJKText(last) && isCJKText(first)) {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(field)
+	}
+	return sb.String()
+}
+
+// Truncate shortens text to at most maxRunes runes. It prefers to end after
+// the last complete sentence, then at the last word boundary, and never splits
+// a multibyte character. Text cut within a sentence ends with Ellipsis, which
+// is not counted in maxRunes.
+func Truncate(text string, maxRunes int) string {
+	text = NormalizeSpace(text)
+	runes := []rune(text)
+	if maxRunes <= 0 || len(runes) <= maxRunes {
+		return text
+	}
+	cut := runes[:maxRunes]
+
+	// End after the last sentence, unless that leaves too little text
+	for i := len(cut) - 1; i >= maxRunes/3; i-- {
+		if isSentenceEnd(runes, i) {
+			return string(cut[:i+1])
+		}
+	}
+
+	// Otherwise cut at a word boundary: a space, or anywhere in CJK text
+	for i := len(cut); i > maxRunes/3; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return strings.TrimRightFunc(string(cut[:i]), isTrailingPunct) + Ellipsis
+		}
+		if isCJKText(runes[i-1]) && isCJKText(runes[i]) {
+			return string(cut[:i]) + Ellipsis
+		}
+	}
+	return string(cut) + Ellipsis
+}
+
+// isSentenceEnd reports whether runes[i] terminates a sentence: CJK
+// terminators always do, Latin ones when followed by a space or the end.
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '。', '！', '？', '．':
+		return true
+	case '.', '!', '?':
+		return i+1 == len(runes) || unicode.IsSpace(runes[i+1])
+	}
+	return false
+}
+
+// isTrailingPunct reports whether r should not end a truncated text
+func isTrailingPunct(r rune) bool {
+	return strings.ContainsRune(",;:-–—(", r)
+}
+
+// isCJKText reports whether r is a CJK character or CJK punctuation
+func isCJKText(r rune) bool {
+	return IsCJK(r) || unicode.In(r, unicode.Katakana) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{"short", "Install the tool.", 50, "Install the tool."},
+		{"whitespace", "Install\n  the   tool.", 50, "Install the tool."},
+		{"sentence boundary", "Install the tool. Then configure it for your project.", 30, "Install the tool."},
+		{"word boundary", "Configure the output format of generated files", 20, "Configure the output..."},
+		{"trailing punctuation", "Install, configure, and run", 12, "Install..."},
+		{"japanese sentence", "ツールをインストールします。次に設定ファイルを作成してください。", 20, "ツールをインストールします。"},
+		{"japanese without sentence end", "設定ファイルの出力形式を変更する方法について説明します", 10, "設定ファイルの出力形..."},
+		{"japanese lines", "設定ファイルを\n作成します。", 50, "設定ファイルを作成します。"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.text, tt.maxRunes); got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.maxRunes, got, tt.want)
+			}
+		})
+	}
+}
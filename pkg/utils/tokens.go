@@ -0,0 +1,22 @@
+package utils
+
+import "unicode"
+
+// EstimateTokens returns a rough token count for s. Latin text is counted at
+// about four characters per token and CJK characters at one token each.
+func EstimateTokens(s string) int {
+	var other, cjk int
+	for _, r := range s {
+		if IsCJK(r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// IsCJK reports whether r is a Chinese, Japanese or Korean character
+func IsCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,20 @@
+package utils
+
+import "strings"
+
+// NormalizeURLPath maps equivalent spellings of a page path to the same key,
+// e.g. "/guide/", "/guide/index.html", "/guide/index.md" and "/guide" all
+// become "/guide". Query strings and fragments are dropped.
+func NormalizeURLPath(p string) string {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	p = strings.TrimSuffix(p, ".html")
+	p = strings.TrimSuffix(p, ".htm")
+	p = strings.TrimSuffix(p, ".md")
+	p = strings.TrimSuffix(p, "/")
+	return strings.TrimSuffix(p, "/index")
+}
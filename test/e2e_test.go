@@ -244,6 +244,65 @@ func TestE2EWithSitemap(t *testing.T) {
 	}
 }
 
+// TestE2ECheck tests that --check exits non-zero for a stale output without writing it
+func TestE2ECheck(t *testing.T) {
+	// Get the project root directory
+	rootDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	rootDir = filepath.Dir(rootDir) // Assuming test is run from the test directory
+
+	// Always build the tool to ensure it's available
+	binaryPath := filepath.Join(rootDir, "llmstxt-gen")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, ".")
+	buildCmd.Dir = rootDir
+	buildOutput, err := buildCmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to build tool: %v\nOutput: %s", err, buildOutput)
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "llms.txt")
+	testdataDir := filepath.Join(rootDir, "testdata", "html")
+	run := func(extra ...string) ([]byte, error) {
+		args := append([]string{"--html-dir", testdataDir, "--output-file", outputFile, "--project-name", "Test Documentation"}, extra...)
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Dir = rootDir
+		return cmd.CombinedOutput()
+	}
+
+	// Generate the output, then check it
+	if output, err := run(); err != nil {
+		t.Fatalf("Failed to run tool: %v\nOutput: %s", err, output)
+	}
+	generated, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if output, err := run("--check"); err != nil {
+		t.Fatalf("--check failed for an up-to-date output: %v\nOutput: %s", err, output)
+	}
+
+	// Make the output stale
+	stale := strings.Replace(string(generated), "# Test Documentation", "# Old Name", 1)
+	if err := os.WriteFile(outputFile, []byte(stale), 0644); err != nil {
+		t.Fatalf("Failed to write output file: %v", err)
+	}
+	output, err := run("--check")
+	if err == nil {
+		t.Fatalf("--check succeeded for a stale output\nOutput: %s", output)
+	}
+	if !strings.Contains(string(output), "-# Old Name") || !strings.Contains(string(output), "+# Test Documentation") {
+		t.Errorf("Unified diff not found in command output:\n%s", output)
+	}
+	if !strings.Contains(string(output), outputFile+" out of date") {
+		t.Errorf("Stale path not listed in command output:\n%s", output)
+	}
+	if content, _ := os.ReadFile(outputFile); string(content) != stale {
+		t.Errorf("--check modified the output file")
+	}
+}
+
 // normalizeWhitespace removes extra whitespace and normalizes line endings
 func normalizeWhitespace(s string) string {
 	// Replace all whitespace sequences with a single space